@@ -0,0 +1,269 @@
+package sqrl
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// cachedSqlizer holds SQL and args built by a Sqlizer once
+type cachedSqlizer struct {
+	sql  string
+	args []interface{}
+	err  error
+}
+
+// Cached builds s once and returns a Sqlizer returning the stored result, which
+// is useful on hot paths running the same query many times:
+//
+//	var activeUsers = sqrl.Cached(sqrl.Select("*").From("users").Where("active = ?", true))
+//	...
+//	rows, err := sqrl.QueryWith(db, activeUsers)
+//
+// The stored result is invalidated when s is changed, e.g. by Where, and s is
+// built again by the next ToSql. Changes of builders embedded into s, like
+// subqueries, are tracked too. Args, maps like Eq and Sqlizers of other
+// packages are compared by identity only, so their contents must not be
+// changed in place. Checking s for changes walks it without allocations,
+// which is cheaper than building it, but not free.
+//
+// Statement builders are cached with placeholders left as is too, so the
+// result could still be embedded into another statement as a subquery.
+func Cached(s Sqlizer) Sqlizer {
+	c := &cachedStatement{source: s}
+	c.build()
+	if _, ok := s.(rawSqlizer); !ok {
+		return &cachedExpr{c}
+	}
+	return c
+}
+
+// ToSql returns the stored SQL string and a copy of the stored args.
+func (c *cachedSqlizer) ToSql() (string, []interface{}, error) {
+	if c.err != nil {
+		return "", nil, c.err
+	}
+	return c.sql, c.copyArgs(), nil
+}
+
+func (c *cachedSqlizer) copyArgs() []interface{} {
+	if c.args == nil {
+		return nil
+	}
+	args := make([]interface{}, len(c.args))
+	copy(args, c.args)
+	return args
+}
+
+// cachedStatement is a cached Sqlizer, rebuilt when its source is changed
+type cachedStatement struct {
+	source Sqlizer
+
+	mu          sync.RWMutex
+	fingerprint fingerprint
+	result      cachedSqlizer
+	raw         cachedSqlizer
+}
+
+// cachedExpr is a cached Sqlizer other than a statement builder, which has no
+// raw SQL to be embedded into another statement
+type cachedExpr struct {
+	c *cachedStatement
+}
+
+func (c *cachedExpr) ToSql() (string, []interface{}, error) {
+	return c.c.ToSql()
+}
+
+// build builds the source and records its fingerprint. c.mu must be held
+// unless c is not shared yet.
+func (c *cachedStatement) build() {
+	c.result = cachedSqlizer{}
+	c.result.sql, c.result.args, c.result.err = c.source.ToSql()
+	if raw, ok := c.source.(rawSqlizer); ok {
+		c.raw = cachedSqlizer{}
+		c.raw.sql, c.raw.args, c.raw.err = raw.toSqlRaw()
+	}
+	c.fingerprint.record(c.source)
+}
+
+// current returns stored results, building the source again if it was changed.
+func (c *cachedStatement) current() (result, raw cachedSqlizer) {
+	c.mu.RLock()
+	if c.fingerprint.matches(c.source) {
+		result, raw = c.result, c.raw
+		c.mu.RUnlock()
+		return
+	}
+	c.mu.RUnlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.fingerprint.matches(c.source) {
+		c.build()
+	}
+	return c.result, c.raw
+}
+
+func (c *cachedStatement) ToSql() (string, []interface{}, error) {
+	result, _ := c.current()
+	return result.ToSql()
+}
+
+func (c *cachedStatement) toSqlRaw() (string, []interface{}, error) {
+	_, raw := c.current()
+	return raw.ToSql()
+}
+
+// maxFingerprintDepth limits nesting of pointers followed by a fingerprint,
+// which guards against cycles
+const maxFingerprintDepth = 32
+
+var (
+	sqrlPkgPath              = reflect.TypeOf(cachedSqlizer{}).PkgPath()
+	sqrlSubPkgPrefix         = sqrlPkgPath + "/"
+	statementBuilderTypeType = reflect.TypeOf(StatementBuilderType{})
+	cachedStatementType      = reflect.TypeOf(cachedStatement{})
+
+	// fields of StatementBuilderType changing SQL, runner and context do not
+	statementBuilderSqlFields = fieldIndexes(statementBuilderTypeType, "placeholderFormat", "dialect", "postProcessors")
+	cachedStatementSource     = fieldIndexes(cachedStatementType, "source")
+)
+
+func fieldIndexes(t reflect.Type, names ...string) []int {
+	indexes := make([]int, len(names))
+	for i, name := range names {
+		field, _ := t.FieldByName(name)
+		indexes[i] = field.Index[0]
+	}
+	return indexes
+}
+
+// fingerprintToken is a single value read from a Sqlizer by fingerprint
+type fingerprintToken struct {
+	n uint64
+	s string
+	t reflect.Type
+}
+
+// fingerprint is a flat record of values making up a Sqlizer, used to tell
+// whether it was changed since it was built. Pointers to types of this package
+// are followed, other pointers, maps and funcs are recorded by identity.
+type fingerprint struct {
+	tokens []fingerprintToken
+
+	// check state of matches
+	checking bool
+	pos      int
+	changed  bool
+}
+
+func (f *fingerprint) record(s Sqlizer) {
+	f.tokens = f.tokens[:0]
+	f.checking = false
+	f.walk(reflect.ValueOf(&s).Elem(), 0)
+}
+
+// matches tells whether s still has the recorded values. It does not change
+// the recorded tokens, so it could be called concurrently on a copy.
+func (f fingerprint) matches(s Sqlizer) bool {
+	f.checking = true
+	f.walk(reflect.ValueOf(&s).Elem(), 0)
+	return !f.changed && f.pos == len(f.tokens)
+}
+
+func (f *fingerprint) emit(tok fingerprintToken) {
+	if !f.checking {
+		f.tokens = append(f.tokens, tok)
+		return
+	}
+	if f.pos >= len(f.tokens) || f.tokens[f.pos] != tok {
+		f.changed = true
+		return
+	}
+	f.pos++
+}
+
+func (f *fingerprint) walk(v reflect.Value, depth int) {
+	if f.changed {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		n := uint64(0)
+		if v.Bool() {
+			n = 1
+		}
+		f.emit(fingerprintToken{n: n})
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f.emit(fingerprintToken{n: uint64(v.Int())})
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f.emit(fingerprintToken{n: v.Uint()})
+	case reflect.Float32, reflect.Float64:
+		f.emit(fingerprintToken{n: math.Float64bits(v.Float())})
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		f.emit(fingerprintToken{n: math.Float64bits(real(c))})
+		f.emit(fingerprintToken{n: math.Float64bits(imag(c))})
+	case reflect.String:
+		f.emit(fingerprintToken{s: v.String()})
+	case reflect.Interface:
+		if v.IsNil() {
+			f.emit(fingerprintToken{})
+			return
+		}
+		f.emit(fingerprintToken{t: v.Elem().Type()})
+		f.walk(v.Elem(), depth)
+	case reflect.Ptr:
+		f.emit(fingerprintToken{n: uint64(v.Pointer()), t: v.Type()})
+		if !v.IsNil() && depth < maxFingerprintDepth && isSqrlType(v.Type().Elem()) {
+			f.walk(v.Elem(), depth+1)
+		}
+	case reflect.Struct:
+		switch v.Type() {
+		case statementBuilderTypeType:
+			f.walkFields(v, statementBuilderSqlFields, depth)
+			return
+		case cachedStatementType:
+			// a nested cache is checked through its source only
+			f.walkFields(v, cachedStatementSource, depth)
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			f.walk(v.Field(i), depth)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			f.walk(v.Index(i), depth)
+		}
+	case reflect.Slice:
+		f.emit(fingerprintToken{n: uint64(v.Pointer())})
+		f.emit(fingerprintToken{n: uint64(v.Len())})
+		if !isSqrlType(v.Type()) && !isSqrlType(v.Type().Elem()) && v.Type().Elem().Kind() != reflect.Interface {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			f.walk(v.Index(i), depth)
+		}
+	default:
+		// maps, funcs, chans and unsafe pointers
+		f.emit(fingerprintToken{n: uint64(v.Pointer()), t: v.Type()})
+		if v.Kind() == reflect.Map || v.Kind() == reflect.Chan {
+			f.emit(fingerprintToken{n: uint64(v.Len())})
+		}
+	}
+}
+
+func (f *fingerprint) walkFields(v reflect.Value, indexes []int, depth int) {
+	for _, i := range indexes {
+		f.walk(v.Field(i), depth)
+	}
+}
+
+// isSqrlType tells whether t is declared by this package or its subpackages.
+func isSqrlType(t reflect.Type) bool {
+	path := t.PkgPath()
+	return path == sqrlPkgPath || strings.HasPrefix(path, sqrlSubPkgPrefix)
+}
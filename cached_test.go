@@ -0,0 +1,160 @@
+package sqrl
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingSqlizer struct {
+	calls int
+}
+
+func (s *countingSqlizer) ToSql() (string, []interface{}, error) {
+	s.calls++
+	return "a = ?", []interface{}{s.calls}, nil
+}
+
+func TestCached(t *testing.T) {
+	s := &countingSqlizer{}
+	c := Cached(s)
+
+	for i := 0; i < 3; i++ {
+		sql, args, err := c.ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, "a = ?", sql)
+		assert.Equal(t, []interface{}{1}, args)
+	}
+	assert.Equal(t, 1, s.calls)
+}
+
+func TestCachedArgsCopy(t *testing.T) {
+	b := Select("*").From("users").Where("id = ?", 1).PlaceholderFormat(Dollar)
+	c := Cached(b)
+
+	sql, args, err := c.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = $1", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	args[0] = 2
+	_, args, _ = c.ToSql()
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestCachedInvalidate(t *testing.T) {
+	b := Select("*").From("users").Where("id = ?", 1).PlaceholderFormat(Dollar)
+	c := Cached(b)
+	b.Where("active = ?", true)
+
+	sql, args, err := c.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = $1 AND active = $2", sql)
+	assert.Equal(t, []interface{}{1, true}, args)
+
+	b.RemoveWhere().Where("id = ?", 3).Limit(5).PlaceholderFormat(Question)
+	sql, args, err = c.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ? LIMIT ?", sql)
+	assert.Equal(t, []interface{}{3, uint64(5)}, args)
+
+	b.Column("name")
+	_, _, err = c.ToSql()
+	assert.NoError(t, err)
+	b.RemoveColumns()
+	_, _, err = c.ToSql()
+	assert.Error(t, err)
+}
+
+func TestCachedInvalidateNested(t *testing.T) {
+	sub := Select("id").From("admins").Where("level > ?", 2)
+	b := Select("*").From("users").UseIndex("a").Where(Eq{"id": sub}).Dialect(MySQLDialect)
+	c := Cached(b)
+	sub.Where("active")
+	b.UseIndex("b")
+
+	sql, args, err := c.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users USE INDEX (a) USE INDEX (b) "+
+		"WHERE id IN (SELECT id FROM admins WHERE level > ? AND active)", sql)
+	assert.Equal(t, []interface{}{2}, args)
+
+	i := Insert("t").Columns("a").Values(1).OnConflict("a")
+	ci := Cached(i)
+	i.DoUpdate(map[string]interface{}{"a": 2}).PlaceholderFormat(Dollar)
+	sql, _, err = ci.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (a) VALUES ($1) ON CONFLICT (a) DO UPDATE SET a = $2", sql)
+}
+
+func TestCachedConcurrent(t *testing.T) {
+	c := Cached(Select("*").From("users").Where("id = ?", 1))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				sql, _, err := c.ToSql()
+				assert.NoError(t, err)
+				assert.Equal(t, "SELECT * FROM users WHERE id = ?", sql)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCachedSubquery(t *testing.T) {
+	sub := Cached(Select("id").From("admins").Where("level > ?", 2).PlaceholderFormat(Dollar))
+	sql, args, err := Select("*").From("users").
+		Where("active = ?", true).
+		Where(Eq{"id": sub}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE active = $1 AND id IN (SELECT id FROM admins WHERE level > $2)", sql)
+	assert.Equal(t, []interface{}{true, 2}, args)
+}
+
+func TestCachedError(t *testing.T) {
+	_, _, err := Cached(Select()).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Cached(errSqlizer{}).ToSql()
+	assert.Error(t, err)
+}
+
+func BenchmarkCachedToSql(b *testing.B) {
+	qb := Cached(Select("a", "b").
+		From("e").
+		Join("j2").
+		Where("f = ?", 4).
+		Where(Eq{"i": []int{7, 8, 9}}).
+		Where(Or{Expr("j = ?", 10), And{Eq{"k": 11}, Expr("true")}}).
+		OrderBy("o ASC", "p DESC").
+		Limit(12).
+		PlaceholderFormat(Dollar))
+
+	for i := 0; i < b.N; i++ {
+		qb.ToSql()
+	}
+}
+
+func BenchmarkUncachedToSql(b *testing.B) {
+	qb := Select("a", "b").
+		From("e").
+		Join("j2").
+		Where("f = ?", 4).
+		Where(Eq{"i": []int{7, 8, 9}}).
+		Where(Or{Expr("j = ?", 10), And{Eq{"k": 11}, Expr("true")}}).
+		OrderBy("o ASC", "p DESC").
+		Limit(12).
+		PlaceholderFormat(Dollar)
+
+	for i := 0; i < b.N; i++ {
+		qb.ToSql()
+	}
+}
@@ -21,7 +21,7 @@ func (b *sqlizerBuffer) WriteSql(item Sqlizer) {
 
 	var str string
 	var args []interface{}
-	str, args, b.err = item.ToSql()
+	str, args, b.err = nestedToSql(item)
 
 	if b.err != nil {
 		return
@@ -43,7 +43,18 @@ type whenPart struct {
 }
 
 func newWhenPart(when interface{}, then interface{}) whenPart {
-	return whenPart{newPart(when), newPart(then)}
+	return whenPart{newCasePart(when), newCasePart(then)}
+}
+
+// newCasePart makes a part of CASE construct: strings are written as is,
+// Sqlizers are embedded and any other value is bound as an argument
+func newCasePart(v interface{}) Sqlizer {
+	switch v.(type) {
+	case string, Sqlizer:
+		return newPart(v)
+	default:
+		return Expr("?", v)
+	}
 }
 
 // CaseBuilder builds SQL CASE construct which could be used as parts of queries.
@@ -87,11 +98,12 @@ func (b *CaseBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 // what sets optional value for CASE construct "CASE [value] ..."
 func (b *CaseBuilder) what(expr interface{}) *CaseBuilder {
-	b.whatPart = newPart(expr)
+	b.whatPart = newCasePart(expr)
 	return b
 }
 
-// When adds "WHEN ... THEN ..." part to CASE construct
+// When adds "WHEN ... THEN ..." part to CASE construct.
+// Strings are written as is, Sqlizers are embedded, other values are bound as args.
 func (b *CaseBuilder) When(when interface{}, then interface{}) *CaseBuilder {
 	// TODO: performance hint: replace slice of WhenPart with just slice of parts
 	// where even indices of the slice belong to "when"s and odd indices belong to "then"s
@@ -99,9 +111,14 @@ func (b *CaseBuilder) When(when interface{}, then interface{}) *CaseBuilder {
 	return b
 }
 
-// Else sets optional "ELSE ..." part for CASE construct
+// Else sets optional "ELSE ..." part for CASE construct.
+// Else(nil) removes the part, use Expr("NULL") to write "ELSE NULL".
 func (b *CaseBuilder) Else(expr interface{}) *CaseBuilder {
-	b.elsePart = newPart(expr)
+	if expr == nil {
+		b.elsePart = nil
+		return b
+	}
+	b.elsePart = newCasePart(expr)
 	return b
 
 }
@@ -139,3 +139,51 @@ func TestCaseWithNoWhenClause(t *testing.T) {
 
 	assert.Equal(t, "case expression must contain at lease one WHEN clause", err.Error())
 }
+
+func TestCaseWithBoundValues(t *testing.T) {
+	caseStmt := Case("status").
+		When(1, "'active'").
+		When(2, Expr("? || ?", "in", "active")).
+		Else(0)
+
+	sql, args, err := Select("id").
+		Column(Alias(caseStmt, "status_name")).
+		From("users").
+		Where("id > ?", 100).
+		PlaceholderFormat(Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT id, (CASE status " +
+		"WHEN $1 THEN 'active' " +
+		"WHEN $2 THEN $3 || $4 " +
+		"ELSE $5 " +
+		"END) AS status_name " +
+		"FROM users WHERE id > $6"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{1, 2, "in", "active", 0, 100}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestCaseSearchedWithBoundResults(t *testing.T) {
+	caseStmt := Case().
+		When(Lt{"age": 18}, 0.5).
+		When(Expr("age > ?", 65), 0.7).
+		Else(1.0)
+
+	sql, args, err := caseStmt.ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "CASE WHEN age < ? THEN ? WHEN age > ? THEN ? ELSE ? END", sql)
+	assert.Equal(t, []interface{}{18, 0.5, 65, 0.7, 1.0}, args)
+}
+
+func TestCaseElseNil(t *testing.T) {
+	sql, args, err := Case("kind").When("1", 2).Else(3).Else(nil).ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "CASE kind WHEN 1 THEN ? END", sql)
+	assert.Equal(t, []interface{}{2}, args)
+}
@@ -0,0 +1,104 @@
+package sqrl
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DebugSqlizer returns SQL of s with args inlined as literals, e.g.
+// "SELECT * FROM users WHERE name = 'moe' AND deleted_at IS NULL".
+//
+// The result is meant for logging and debugging only. Quoting of args is
+// simplified and differs between databases, so it is NOT safe to execute
+// and must never be used to avoid bound args.
+//
+// Post processors of a statement builder run after args are inlined, like
+// ToSql runs them after placeholders are replaced, so e.g. comments and
+// sanitizers show up in the output and a question mark added by a post
+// processor is not taken for a placeholder. Args returned by post processors
+// are not inlined.
+//
+// Placeholders without args are left as is and a note about the mismatch is
+// appended, as well as for args without placeholders. An error of ToSql is
+// returned as "[DebugSqlizer error: ...]".
+func DebugSqlizer(s Sqlizer) string {
+	sql, args, err := nestedToSql(s)
+	if err != nil {
+		return fmt.Sprintf("[DebugSqlizer error: %s]", err)
+	}
+
+	placeholders := 0
+	sql, _ = scanPlaceholders(sql, "?", func(buf *bytes.Buffer, i int) error {
+		placeholders = i
+		if i > len(args) {
+			buf.WriteString("?")
+			return nil
+		}
+		buf.WriteString(debugLiteral(args[i-1]))
+		return nil
+	})
+
+	if pp, ok := s.(postProcessor); ok {
+		sql, _, err = pp.postProcess(sql, args)
+		if err != nil {
+			return fmt.Sprintf("[DebugSqlizer error: %s]", err)
+		}
+	}
+
+	if placeholders != len(args) {
+		sql = fmt.Sprintf("%s [DebugSqlizer mismatch: %d placeholders, %d args]", sql, placeholders, len(args))
+	}
+	return sql
+}
+
+// postProcessor is implemented by statement builders running post processors
+// after their SQL is built.
+type postProcessor interface {
+	postProcess(sql string, args []interface{}) (string, []interface{}, error)
+}
+
+// debugLiteral formats arg as SQL literal for DebugSqlizer
+func debugLiteral(arg interface{}) string {
+	if named, ok := arg.(sql.NamedArg); ok {
+		arg = named.Value
+	}
+	if valuer, ok := arg.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return fmt.Sprintf("[error: %s]", err)
+		}
+		arg = value
+	}
+
+	switch v := arg.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return quoteLiteral(v)
+	case []byte:
+		if v == nil {
+			return "NULL"
+		}
+		return "X'" + hex.EncodeToString(v) + "'"
+	case time.Time:
+		return quoteLiteral(v.Format("2006-01-02 15:04:05.999999999Z07:00"))
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprintf("%v", v)
+	default:
+		return quoteLiteral(fmt.Sprintf("%v", v))
+	}
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
@@ -0,0 +1,79 @@
+package sqrl
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugSqlizer(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	b := Select("*").
+		From("users").
+		Where(Eq{"name": "o'moe"}).
+		Where("age > ? AND score < ?", 13, 1.5).
+		Where("active = ? AND deleted_at IS ?", true, nil).
+		Where("created_at = ? AND data = ?", created, []byte("hi")).
+		Where("nick = ? AND json ?? 'key'", sql.NullString{String: "m", Valid: true}).
+		PlaceholderFormat(Dollar)
+
+	expected := "SELECT * FROM users WHERE name = 'o''moe' AND age > 13 AND score < 1.5 " +
+		"AND active = TRUE AND deleted_at IS NULL " +
+		"AND created_at = '2020-01-02 03:04:05Z' AND data = X'6869' " +
+		"AND nick = 'm' AND json ? 'key'"
+	assert.Equal(t, expected, DebugSqlizer(b))
+}
+
+func TestDebugSqlizerMismatch(t *testing.T) {
+	assert.Equal(t, "a = 1 AND b = ? [DebugSqlizer mismatch: 2 placeholders, 1 args]",
+		DebugSqlizer(Expr("a = ? AND b = ?", 1)))
+
+	assert.Equal(t, "a = 1 [DebugSqlizer mismatch: 1 placeholders, 2 args]",
+		DebugSqlizer(Expr("a = ?", 1, 2)))
+}
+
+type errSqlizer struct{}
+
+func (errSqlizer) ToSql() (string, []interface{}, error) {
+	return "", nil, errors.New("broken")
+}
+
+func TestDebugSqlizerError(t *testing.T) {
+	assert.Equal(t, "[DebugSqlizer error: broken]", DebugSqlizer(errSqlizer{}))
+	assert.Equal(t, "[DebugSqlizer error: select statements must have at least one result column]", DebugSqlizer(Select()))
+}
+
+func TestDebugSqlizerNamedArg(t *testing.T) {
+	assert.Equal(t, "a = 'moe'", DebugSqlizer(Expr("a = ?", sql.Named("name", "moe"))))
+}
+
+func TestDebugSqlizerPostProcess(t *testing.T) {
+	b := StatementBuilder.
+		PostProcess(func(sql string, args []interface{}) (string, []interface{}, error) {
+			return sql + " /* app */", args, nil
+		}).
+		Sanitize(CollapseWhitespace).
+		PlaceholderFormat(Dollar).
+		Select("*").From("users").Where("name = ?\n\tAND age > ?", "moe", 3)
+
+	assert.Equal(t, "SELECT * FROM users WHERE name = 'moe' AND age > 3 /* app */", DebugSqlizer(b))
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE name = $1 AND age > $2 /* app */", sql)
+
+	question := StatementBuilder.
+		PostProcess(func(sql string, args []interface{}) (string, []interface{}, error) {
+			return "/* who? */ " + sql, args, nil
+		}).
+		Select("*").From("users").Where("name = ? AND age > ?", "moe", 3)
+	assert.Equal(t, "/* who? */ SELECT * FROM users WHERE name = 'moe' AND age > 3", DebugSqlizer(question))
+
+	failing := StatementBuilder.PostProcess(func(sql string, args []interface{}) (string, []interface{}, error) {
+		return "", nil, errors.New("rejected")
+	}).Select("a")
+	assert.Equal(t, "[DebugSqlizer error: rejected]", DebugSqlizer(failing))
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"strconv"
 	"strings"
 )
 
@@ -16,11 +15,12 @@ type DeleteBuilder struct {
 	StatementBuilderType
 
 	returning
+	with
 
 	prefixes   exprs
 	what       []string
 	from       string
-	joins      []string
+	joins      []Sqlizer
 	usingParts []Sqlizer
 	whereParts []Sqlizer
 	orderBys   []string
@@ -44,9 +44,16 @@ func (b *DeleteBuilder) RunWith(runner BaseRunner) *DeleteBuilder {
 	return b
 }
 
+// WithContext sets ctx to be used by Exec, Query, QueryRow and Scan, which do not take a context.
+// See SelectBuilder.WithContext.
+func (b *DeleteBuilder) WithContext(ctx context.Context) *DeleteBuilder {
+	b.ctx = ctx
+	return b
+}
+
 // Exec builds and Execs the query with the Runner set by RunWith.
 func (b *DeleteBuilder) Exec() (sql.Result, error) {
-	return b.ExecContext(context.Background())
+	return b.ExecContext(b.runContext())
 }
 
 // ExecContext builds and Execs the query with the Runner set by RunWith using given context.
@@ -59,7 +66,7 @@ func (b *DeleteBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
 
 // Query builds and Querys the query with the Runner set by RunWith.
 func (b *DeleteBuilder) Query() (*sql.Rows, error) {
-	return b.QueryContext(context.Background())
+	return b.QueryContext(b.runContext())
 }
 
 // QueryContext builds and runs the query using given context and Query command.
@@ -72,7 +79,7 @@ func (b *DeleteBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
 
 // QueryRow builds and QueryRows the query with the Runner set by RunWith.
 func (b *DeleteBuilder) QueryRow() RowScanner {
-	return b.QueryRowContext(context.Background())
+	return b.QueryRowContext(b.runContext())
 }
 
 // QueryRowContext builds and runs the query using given context.
@@ -99,8 +106,30 @@ func (b *DeleteBuilder) PlaceholderFormat(f PlaceholderFormat) *DeleteBuilder {
 	return b
 }
 
+// Dialect sets Dialect (e.g. PostgresDialect or MySQLDialect) for the query.
+// PlaceholderFormat is set to the one used by the dialect.
+func (b *DeleteBuilder) Dialect(d Dialect) *DeleteBuilder {
+	b.dialect = d
+	b.placeholderFormat = d.PlaceholderFormat()
+	return b
+}
+
 // ToSql builds the query into a SQL string and bound args.
-func (b *DeleteBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+func (b *DeleteBuilder) ToSql() (string, []interface{}, error) {
+	sql, args, err := b.toSqlRaw()
+	if err != nil {
+		return "", nil, err
+	}
+	return b.formatSql(sql, args)
+}
+
+// MustSql builds the query like ToSql and panics on error, see MustSql.
+func (b *DeleteBuilder) MustSql() (string, []interface{}) {
+	return MustSql(b)
+}
+
+// toSqlRaw builds the query leaving placeholders as is.
+func (b *DeleteBuilder) toSqlRaw() (sqlStr string, args []interface{}, err error) {
 	if len(b.from) == 0 {
 		err = fmt.Errorf("delete statements must specify a From table")
 		return
@@ -109,14 +138,34 @@ func (b *DeleteBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 	sql := &bytes.Buffer{}
 
 	if len(b.prefixes) > 0 {
-		args, _ = b.prefixes.AppendToSql(sql, " ", args)
+		args, err = b.prefixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
+	if len(b.ctes) > 0 {
+		args, err = b.with.AppendToSql(sql, args)
+		if err != nil {
+			return
+		}
+	}
+
+	// MySQL has no USING clause of PostgreSQL kind, joins are used there instead:
+	// "DELETE a FROM a JOIN b WHERE ..."
+	usingJoins := len(b.usingParts) > 0 && b.dialect == MySQLDialect
+	// MySQL multi-table delete requires tables to delete from: "DELETE a FROM a JOIN ..."
+	multiTable := b.dialect == MySQLDialect && (usingJoins || len(b.joins) > 0)
+
 	sql.WriteString("DELETE ")
+	if multiTable && len(b.what) == 0 {
+		sql.WriteString(b.from)
+		sql.WriteString(" ")
+	}
 	// following condition helps to avoid duplicate "from" value in DELETE query
 	// e.g. "DELETE a FROM a ..." which is valid for MySQL but not for PostgreSQL
-	if len(b.what) > 0 && (len(b.what) != 1 || b.what[0] != b.from) {
+	if len(b.what) > 0 && (multiTable || len(b.what) != 1 || b.what[0] != b.from) {
 		sql.WriteString(strings.Join(b.what, ", "))
 		sql.WriteString(" ")
 	}
@@ -124,14 +173,25 @@ func (b *DeleteBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 	sql.WriteString("FROM ")
 	sql.WriteString(b.from)
 
+	if usingJoins {
+		sql.WriteString(" JOIN ")
+		args, err = appendToSql(b.usingParts, sql, " JOIN ", b.exprDialect(), args)
+		if err != nil {
+			return
+		}
+	}
+
 	if len(b.joins) > 0 {
 		sql.WriteString(" ")
-		sql.WriteString(strings.Join(b.joins, " "))
+		args, err = appendToSql(b.joins, sql, " ", b.exprDialect(), args)
+		if err != nil {
+			return
+		}
 	}
 
-	if len(b.usingParts) > 0 {
+	if len(b.usingParts) > 0 && !usingJoins {
 		sql.WriteString(" USING ")
-		args, err = appendToSql(b.usingParts, sql, ", ", args)
+		args, err = appendToSql(b.usingParts, sql, ", ", b.exprDialect(), args)
 		if err != nil {
 			return
 		}
@@ -139,7 +199,7 @@ func (b *DeleteBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.whereParts) > 0 {
 		sql.WriteString(" WHERE ")
-		args, err = appendToSql(b.whereParts, sql, " AND ", args)
+		args, err = appendToSql(b.whereParts, sql, " AND ", b.exprDialect(), args)
 		if err != nil {
 			return
 		}
@@ -150,19 +210,16 @@ func (b *DeleteBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 		sql.WriteString(strings.Join(b.orderBys, ", "))
 	}
 
-	// TODO: limit == 0 and offswt == 0 are valid. Need to go dbr way and implement offsetValid and limitValid
-	if b.limitValid {
-		sql.WriteString(" LIMIT ")
-		sql.WriteString(strconv.FormatUint(b.limit, 10))
+	if err = checkDmlLimitOffset("DELETE", b.dialect, b.limitValid, b.offsetValid); err != nil {
+		return
 	}
-
-	if b.offsetValid {
-		sql.WriteString(" OFFSET ")
-		sql.WriteString(strconv.FormatUint(b.offset, 10))
+	args, err = appendLimitOffset(sql, b.dialect, len(b.orderBys) > 0, b.limit, b.limitValid, b.offset, b.offsetValid, args)
+	if err != nil {
+		return
 	}
 
 	if len(b.returning) > 0 {
-		args, err = b.returning.AppendToSql(sql, args)
+		args, err = b.returning.AppendToSql(sql, b.dialect, args)
 		if err != nil {
 			return
 		}
@@ -170,14 +227,34 @@ func (b *DeleteBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = b.suffixes.AppendToSql(sql, " ", args)
+		args, err = b.suffixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 	}
 
-	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	sqlStr = sql.String()
 	return
 }
 
-// Prefix adds an expression to the beginning of the query
+// With adds a common table expression to WITH clause of the query:
+// "WITH name AS (query) DELETE ...".
+//
+// The name is written verbatim, so column list could be given as well, e.g. "t(a, b)".
+func (b *DeleteBuilder) With(name string, query Sqlizer) *DeleteBuilder {
+	b.with.With(name, query)
+	return b
+}
+
+// WithRecursive adds a common table expression to WITH clause of the query,
+// making the clause "WITH RECURSIVE".
+func (b *DeleteBuilder) WithRecursive(name string, query Sqlizer) *DeleteBuilder {
+	b.with.WithRecursive(name, query)
+	return b
+}
+
+// Prefix adds an expression to the beginning of the query.
+// Sqlizer args are embedded in place of their placeholders.
 func (b *DeleteBuilder) Prefix(sql string, args ...interface{}) *DeleteBuilder {
 	b.prefixes = append(b.prefixes, Expr(sql, args...))
 	return b
@@ -208,7 +285,8 @@ func (b *DeleteBuilder) What(what ...string) *DeleteBuilder {
 
 // Using sets the USING clause of the query.
 //
-// DELETE ... USING is an MySQL/PostgreSQL specific extension
+// DELETE ... USING is an MySQL/PostgreSQL specific extension.
+// With MySQL dialect the tables are joined instead: "DELETE a FROM a JOIN b ...".
 func (b *DeleteBuilder) Using(tables ...string) *DeleteBuilder {
 	parts := make([]Sqlizer, len(tables))
 	for i, table := range tables {
@@ -233,12 +311,56 @@ func (b *DeleteBuilder) Where(pred interface{}, args ...interface{}) *DeleteBuil
 	return b
 }
 
+// WhereIf adds WHERE expressions like Where does, but only if cond is true.
+func (b *DeleteBuilder) WhereIf(cond bool, pred interface{}, args ...interface{}) *DeleteBuilder {
+	if cond {
+		b.Where(pred, args...)
+	}
+	return b
+}
+
 // OrderBy adds ORDER BY expressions to the query.
 func (b *DeleteBuilder) OrderBy(orderBys ...string) *DeleteBuilder {
 	b.orderBys = append(b.orderBys, orderBys...)
 	return b
 }
 
+// OrderByIf adds ORDER BY expressions like OrderBy does, but only if cond is true.
+func (b *DeleteBuilder) OrderByIf(cond bool, orderBys ...string) *DeleteBuilder {
+	if cond {
+		b.OrderBy(orderBys...)
+	}
+	return b
+}
+
+// Clone returns a copy of the builder, so parts could be added to or removed
+// from the copy without changing the original query, see SelectBuilder.Clone.
+func (b *DeleteBuilder) Clone() *DeleteBuilder {
+	c := *b
+	c.returning = returning(cloneSqlizers(b.returning))
+	c.with = b.with.clone()
+	c.prefixes = append(exprs(nil), b.prefixes...)
+	c.what = cloneStrings(b.what)
+	c.joins = cloneSqlizers(b.joins)
+	c.usingParts = cloneSqlizers(b.usingParts)
+	c.whereParts = cloneSqlizers(b.whereParts)
+	c.orderBys = cloneStrings(b.orderBys)
+	c.suffixes = append(exprs(nil), b.suffixes...)
+	return &c
+}
+
+// RemoveWhere removes all WHERE expressions from the query.
+func (b *DeleteBuilder) RemoveWhere() *DeleteBuilder {
+	b.whereParts = nil
+	return b
+}
+
+// RemoveOrderBys removes ORDER BY clause from the query.
+func (b *DeleteBuilder) RemoveOrderBys() *DeleteBuilder {
+	b.orderBys = nil
+	return b
+}
+
 // Limit sets a LIMIT clause on the query.
 func (b *DeleteBuilder) Limit(limit uint64) *DeleteBuilder {
 	b.limit = limit
@@ -269,29 +391,36 @@ func (b *DeleteBuilder) ReturningSelect(from *SelectBuilder, alias string) *Dele
 	return b
 }
 
-// Suffix adds an expression to the end of the query
+// Suffix adds an expression to the end of the query.
+// Sqlizer args are embedded in place of their placeholders.
 func (b *DeleteBuilder) Suffix(sql string, args ...interface{}) *DeleteBuilder {
 	b.suffixes = append(b.suffixes, Expr(sql, args...))
 	return b
 }
 
 // JoinClause adds a join clause to the query.
-func (b *DeleteBuilder) JoinClause(join string) *DeleteBuilder {
-	b.joins = append(b.joins, join)
+//
+// DELETE ... JOIN is MySQL specific extension
+func (b *DeleteBuilder) JoinClause(pred interface{}, args ...interface{}) *DeleteBuilder {
+	b.joins = append(b.joins, newPart(pred, args...))
 	return b
 }
 
 // Join adds a JOIN clause to the query.
-func (b *DeleteBuilder) Join(join string) *DeleteBuilder {
-	return b.JoinClause("JOIN " + join)
+// The join condition could be a Sqlizer, see SelectBuilder.Join.
+func (b *DeleteBuilder) Join(join string, rest ...interface{}) *DeleteBuilder {
+	b.joins = append(b.joins, newJoinPart("JOIN "+join, rest))
+	return b
 }
 
 // LeftJoin adds a LEFT JOIN clause to the query.
-func (b *DeleteBuilder) LeftJoin(join string) *DeleteBuilder {
-	return b.JoinClause("LEFT JOIN " + join)
+func (b *DeleteBuilder) LeftJoin(join string, rest ...interface{}) *DeleteBuilder {
+	b.joins = append(b.joins, newJoinPart("LEFT JOIN "+join, rest))
+	return b
 }
 
 // RightJoin adds a RIGHT JOIN clause to the query.
-func (b *DeleteBuilder) RightJoin(join string) *DeleteBuilder {
-	return b.JoinClause("RIGHT JOIN " + join)
+func (b *DeleteBuilder) RightJoin(join string, rest ...interface{}) *DeleteBuilder {
+	b.joins = append(b.joins, newJoinPart("RIGHT JOIN "+join, rest))
+	return b
 }
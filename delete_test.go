@@ -22,11 +22,11 @@ func TestDeleteBuilderToSql(t *testing.T) {
 
 	expectedSql :=
 		"WITH prefix AS ? " +
-			"DELETE FROM a WHERE b = ? ORDER BY c LIMIT 2 OFFSET 3 " +
+			"DELETE FROM a WHERE b = ? ORDER BY c LIMIT ? OFFSET ? " +
 			"RETURNING ?"
 	assert.Equal(t, expectedSql, sql)
 
-	expectedArgs := []interface{}{0, 1, 4}
+	expectedArgs := []interface{}{0, 1, uint64(2), uint64(3), 4}
 	assert.Equal(t, expectedArgs, args)
 }
 
@@ -139,11 +139,12 @@ func TestDeleteBuilderZeroOffsetLimit(t *testing.T) {
 		Limit(0).
 		Offset(0)
 
-	sql, _, err := qb.ToSql()
+	sql, args, err := qb.ToSql()
 	assert.NoError(t, err)
 
-	expectedSql := "DELETE FROM b LIMIT 0 OFFSET 0"
+	expectedSql := "DELETE FROM b LIMIT ? OFFSET ?"
 	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{uint64(0), uint64(0)}, args)
 }
 
 func TestDeleteBuilderToSqlErr(t *testing.T) {
@@ -221,10 +222,112 @@ func TestIssue11(t *testing.T) {
 	expectedSql := "DELETE a FROM A a " +
 		"JOIN B b ON a.c = b.c " +
 		"WHERE b.d = ? " +
-		"LIMIT 2"
+		"LIMIT ?"
 
 	assert.Equal(t, expectedSql, sql)
 
-	expectedArgs := []interface{}{1}
+	expectedArgs := []interface{}{1, uint64(2)}
 	assert.Equal(t, expectedArgs, args)
 }
+
+func TestDeleteBuilderReturningPlaceholders(t *testing.T) {
+	b := Delete("a").
+		Where("id = ?", 42).
+		Returning("id").
+		ReturningSelect(Select("bar").From("b").Where("b.id = ?", 1), "bar").
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM a WHERE id = $1 RETURNING id, (SELECT bar FROM b WHERE b.id = $2) AS bar", sql)
+	assert.Equal(t, []interface{}{42, 1}, args)
+}
+
+func TestDeleteUsingDialects(t *testing.T) {
+	b := Delete("a").
+		Using("b").
+		UsingSelect(Select("id").From("c").Where("c.kind = ?", "x"), "cc").
+		Where("a.id = b.a_id AND b.id = cc.id AND b.num > ?", 42)
+
+	sql, args, err := b.Dialect(PostgresDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM a USING b, (SELECT id FROM c WHERE c.kind = $1) AS cc "+
+		"WHERE a.id = b.a_id AND b.id = cc.id AND b.num > $2", sql)
+	assert.Equal(t, []interface{}{"x", 42}, args)
+
+	sql, args, err = b.Dialect(MySQLDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE a FROM a JOIN b JOIN (SELECT id FROM c WHERE c.kind = ?) AS cc "+
+		"WHERE a.id = b.a_id AND b.id = cc.id AND b.num > ?", sql)
+	assert.Equal(t, []interface{}{"x", 42}, args)
+}
+
+func TestDeleteJoinArgs(t *testing.T) {
+	b := Delete("a").
+		From("a").
+		Join("b ON b.a_id = a.id AND b.kind = ?", "x").
+		LeftJoin("c ON c.b_id = b.id AND c.num > ?", 1).
+		Where("a.flag = ?", true).
+		Dialect(MySQLDialect)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE a FROM a "+
+		"JOIN b ON b.a_id = a.id AND b.kind = ? "+
+		"LEFT JOIN c ON c.b_id = b.id AND c.num > ? "+
+		"WHERE a.flag = ?", sql)
+	assert.Equal(t, []interface{}{"x", 1, true}, args)
+}
+
+func TestDeleteBuilderIf(t *testing.T) {
+	b := Delete("users").Where("id = ?", 1)
+	before := *b
+
+	b.WhereIf(false, Eq{"name": "moe"}).OrderByIf(false, "name")
+	assert.Equal(t, before, *b)
+
+	sql, args, err := b.WhereIf(true, Eq{"name": "moe"}).OrderByIf(true, "name").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE id = ? AND name = ? ORDER BY name", sql)
+	assert.Equal(t, []interface{}{1, "moe"}, args)
+}
+
+func TestDeleteBuilderJoinOnSqlizer(t *testing.T) {
+	sql, args, err := Delete("a").
+		Join("b", And{Eq{"b.a_id": Expr("a.id")}, Eq{"b.kind": 1}}).
+		Where("a.x = ?", 2).
+		Dialect(MySQLDialect).
+		ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE a FROM a JOIN b ON (b.a_id = a.id AND b.kind = ?) WHERE a.x = ?", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestDeleteBuilderRemoveParts(t *testing.T) {
+	b := Delete("t").Where("x = ?", 1).OrderBy("y").Limit(2)
+	sql, args, err := b.Clone().RemoveWhere().Where("z = ?", 3).RemoveOrderBys().ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t WHERE z = ? LIMIT ?", sql)
+	assert.Equal(t, []interface{}{3, uint64(2)}, args)
+
+	sql, args, err = b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t WHERE x = ? ORDER BY y LIMIT ?", sql)
+	assert.Equal(t, []interface{}{1, uint64(2)}, args)
+}
+
+func TestDeleteBuilderLimitOffsetDialects(t *testing.T) {
+	b := Delete("t").OrderBy("id").Offset(5)
+
+	_, _, err := b.Dialect(OracleDialect).ToSql()
+	assert.EqualError(t, err, "LIMIT and OFFSET of DELETE is not supported by Oracle dialect")
+
+	_, _, err = b.Dialect(SQLServerDialect).ToSql()
+	assert.EqualError(t, err, "LIMIT and OFFSET of DELETE is not supported by SQL Server dialect")
+
+	sql, _, err := Delete("t").Limit(1).Dialect(PostgresDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t LIMIT $1", sql)
+}
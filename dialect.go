@@ -0,0 +1,86 @@
+package sqrl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect identifies SQL flavor of a database.
+//
+// Dialect defines placeholder format and enables dialect specific checks and
+// syntax, e.g. RETURNING clause is rejected for MySQL.
+type Dialect int
+
+const (
+	// NoDialect is the default: queries are built as is, without dialect specific checks.
+	NoDialect Dialect = iota
+	// PostgresDialect is the PostgreSQL dialect, it uses Dollar placeholders.
+	PostgresDialect
+	// MySQLDialect is the MySQL dialect, it uses Question placeholders.
+	MySQLDialect
+	// SQLiteDialect is the SQLite dialect, it uses Question placeholders.
+	SQLiteDialect
+	// SQLServerDialect is the Microsoft SQL Server dialect, it uses AtP placeholders.
+	SQLServerDialect
+	// OracleDialect is the Oracle Database dialect, it uses Colon placeholders.
+	OracleDialect
+)
+
+var dialectNames = map[Dialect]string{
+	NoDialect:        "no",
+	PostgresDialect:  "PostgreSQL",
+	MySQLDialect:     "MySQL",
+	SQLiteDialect:    "SQLite",
+	SQLServerDialect: "SQL Server",
+	OracleDialect:    "Oracle",
+}
+
+// String returns a human readable name of the dialect.
+func (d Dialect) String() string {
+	if name, ok := dialectNames[d]; ok {
+		return name
+	}
+	return fmt.Sprintf("Dialect(%d)", int(d))
+}
+
+// PlaceholderFormat returns PlaceholderFormat used by the dialect.
+func (d Dialect) PlaceholderFormat() PlaceholderFormat {
+	switch d {
+	case PostgresDialect:
+		return Dollar
+	case SQLServerDialect:
+		return AtP
+	case OracleDialect:
+		return Colon
+	default:
+		return Question
+	}
+}
+
+func (d Dialect) supportsReturning() bool {
+	switch d {
+	case NoDialect, PostgresDialect, SQLiteDialect:
+		return true
+	default:
+		return false
+	}
+}
+
+// quoteIdent quotes a single identifier, escaping quotes by doubling them.
+// Question marks are escaped as "??", so they are not taken as placeholders.
+func (d Dialect) quoteIdent(name string) string {
+	name = strings.Replace(name, "?", "??", -1)
+	switch d {
+	case MySQLDialect:
+		return "`" + strings.Replace(name, "`", "``", -1) + "`"
+	case SQLServerDialect:
+		return "[" + strings.Replace(name, "]", "]]", -1) + "]"
+	default:
+		return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+	}
+}
+
+// errNotSupported returns an error about a feature that is not available in the dialect.
+func errNotSupported(feature string, d Dialect) error {
+	return fmt.Errorf("%s is not supported by %s dialect", feature, d)
+}
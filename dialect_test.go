@@ -0,0 +1,139 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialectPlaceholderFormat(t *testing.T) {
+	assert.Equal(t, Dollar, PostgresDialect.PlaceholderFormat())
+	assert.Equal(t, Question, MySQLDialect.PlaceholderFormat())
+	assert.Equal(t, Question, SQLiteDialect.PlaceholderFormat())
+	assert.Equal(t, AtP, SQLServerDialect.PlaceholderFormat())
+	assert.Equal(t, Colon, OracleDialect.PlaceholderFormat())
+	assert.Equal(t, Question, NoDialect.PlaceholderFormat())
+}
+
+func TestDialectString(t *testing.T) {
+	assert.Equal(t, "PostgreSQL", PostgresDialect.String())
+	assert.Equal(t, "MySQL", MySQLDialect.String())
+	assert.Equal(t, "Oracle", OracleDialect.String())
+	assert.Equal(t, "Dialect(42)", Dialect(42).String())
+}
+
+func TestStatementBuilderDialect(t *testing.T) {
+	sb := StatementBuilder.Dialect(PostgresDialect)
+
+	sql, args, err := sb.Select("a").From("b").Where("c = ?", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM b WHERE c = $1", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, _, err = sb.Insert("a").Values(1).Returning("id").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO a VALUES ($1) RETURNING id", sql)
+}
+
+func TestDialectReturning(t *testing.T) {
+	sb := StatementBuilder.Dialect(MySQLDialect)
+
+	_, _, err := sb.Insert("a").Values(1).Returning("id").ToSql()
+	assert.EqualError(t, err, "RETURNING clause is not supported by MySQL dialect")
+
+	_, _, err = sb.Update("a").Set("b", 1).Returning("id").ToSql()
+	assert.Error(t, err)
+
+	_, _, err = sb.Delete("a").Returning("id").ToSql()
+	assert.Error(t, err)
+
+	sql, _, err := Delete("a").Dialect(SQLiteDialect).Returning("id").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM a RETURNING id", sql)
+}
+
+func TestDialectOnConflict(t *testing.T) {
+	b := Insert("a").Columns("id", "b").Values(1, 2).OnConflict("id").DoUpdate(map[string]interface{}{"b": 3})
+
+	sql, args, err := b.Dialect(MySQLDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO a (id,b) VALUES (?,?) ON DUPLICATE KEY UPDATE b = ?", sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+
+	sql, _, err = b.Dialect(SQLiteDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO a (id,b) VALUES (?,?) ON CONFLICT (id) DO UPDATE SET b = ?", sql)
+
+	sql, _, err = b.Dialect(PostgresDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO a (id,b) VALUES ($1,$2) ON CONFLICT (id) DO UPDATE SET b = $3", sql)
+
+	_, _, err = b.Dialect(SQLServerDialect).ToSql()
+	assert.Error(t, err)
+}
+
+func TestIdent(t *testing.T) {
+	tests := []struct {
+		d        Dialect
+		expected string
+	}{
+		{NoDialect, `SELECT "order", "users"."select", "u".* FROM users`},
+		{PostgresDialect, `SELECT "order", "users"."select", "u".* FROM users`},
+		{SQLiteDialect, `SELECT "order", "users"."select", "u".* FROM users`},
+		{OracleDialect, `SELECT "order", "users"."select", "u".* FROM users`},
+		{MySQLDialect, "SELECT `order`, `users`.`select`, `u`.* FROM users"},
+		{SQLServerDialect, `SELECT [order], [users].[select], [u].* FROM users`},
+	}
+
+	for _, test := range tests {
+		sql, args, err := Select().
+			Column(Ident("order")).
+			Column(Ident("users.select")).
+			Column(Ident("u.*")).
+			From("users").
+			Dialect(test.d).
+			ToSql()
+		assert.NoError(t, err, "%s", test.d)
+		assert.Equal(t, test.expected, sql, "%s", test.d)
+		assert.Empty(t, args, "%s", test.d)
+	}
+}
+
+func TestIdentEscape(t *testing.T) {
+	sql, _, err := Ident(`a"b`).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `"a""b"`, sql)
+
+	sql, _, _ = Select().Column(Ident("a`b")).From("t").Dialect(MySQLDialect).ToSql()
+	assert.Equal(t, "SELECT `a``b` FROM t", sql)
+
+	sql, _, _ = Select().Column(Ident("a]b")).From("t").Dialect(SQLServerDialect).ToSql()
+	assert.Equal(t, "SELECT [a]]b] FROM t", sql)
+
+	_, _, err = Ident("").ToSql()
+	assert.Error(t, err)
+}
+
+func TestIdentQuestionMark(t *testing.T) {
+	sql, args, err := Select().Column(Ident("a?b")).Column("x").From("t").Where("y = ?", 1).
+		PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "a?b", x FROM t WHERE y = $1`, sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args, err = Select().Column(Ident("t.a?b")).From("t").Where("y = ?", 1).
+		Dialect(SQLServerDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT [t].[a?b] FROM t WHERE y = @p1", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, _, err = Select().Column(Ident("a?b")).From("t").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "a?b" FROM t`, sql)
+}
+
+func TestIdentAlias(t *testing.T) {
+	sql, _, err := Select().Column(Alias(Ident("order"), "o")).Column("desc").From("t").Dialect(MySQLDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT (`order`) AS o, desc FROM t", sql)
+}
@@ -0,0 +1,153 @@
+package sqrl
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ExplainBuilder builds EXPLAIN statements showing the plan of a query.
+//
+// The query is embedded with all its args, so the options actually running it,
+// like Analyze, get the same args as the query itself.
+type ExplainBuilder struct {
+	StatementBuilderType
+
+	query   Sqlizer
+	analyze bool
+	buffers bool
+	format  string
+}
+
+// NewExplainBuilder creates new instance of ExplainBuilder for given query.
+// The ExplainBuilder runs with settings of b, including the context set by
+// WithContext; call WithContext of the result to replace it.
+func NewExplainBuilder(b StatementBuilderType, query Sqlizer) *ExplainBuilder {
+	return &ExplainBuilder{StatementBuilderType: b, query: query}
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Query.
+func (b *ExplainBuilder) RunWith(runner BaseRunner) *ExplainBuilder {
+	b.runWith = wrapRunner(runner)
+	return b
+}
+
+// WithContext sets ctx to be used by Query, which do not take a context.
+// See SelectBuilder.WithContext.
+func (b *ExplainBuilder) WithContext(ctx context.Context) *ExplainBuilder {
+	b.ctx = ctx
+	return b
+}
+
+// Query builds and Querys the query with the Runner set by RunWith.
+func (b *ExplainBuilder) Query() (*sql.Rows, error) {
+	return b.QueryContext(b.runContext())
+}
+
+// QueryContext builds and Querys the query with the Runner set by RunWith in given context.
+func (b *ExplainBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	if b.runWith == nil {
+		return nil, ErrRunnerNotSet
+	}
+	return QueryWithContext(ctx, b.runWith, b)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// query.
+func (b *ExplainBuilder) PlaceholderFormat(f PlaceholderFormat) *ExplainBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// Dialect sets Dialect (e.g. PostgresDialect or MySQLDialect) for the query.
+// PlaceholderFormat is set to the one used by the dialect.
+func (b *ExplainBuilder) Dialect(d Dialect) *ExplainBuilder {
+	b.dialect = d
+	b.placeholderFormat = d.PlaceholderFormat()
+	return b
+}
+
+// Analyze makes the database run the query and report actual timings.
+func (b *ExplainBuilder) Analyze() *ExplainBuilder {
+	b.analyze = true
+	return b
+}
+
+// Buffers adds buffers usage to the plan, it is PostgreSQL specific.
+func (b *ExplainBuilder) Buffers() *ExplainBuilder {
+	b.buffers = true
+	return b
+}
+
+// Format sets output format of the plan, e.g. "JSON". It is supported by
+// PostgreSQL and MySQL dialects.
+func (b *ExplainBuilder) Format(format string) *ExplainBuilder {
+	b.format = format
+	return b
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (b *ExplainBuilder) ToSql() (string, []interface{}, error) {
+	sql, args, err := b.toSqlRaw()
+	if err != nil {
+		return "", nil, err
+	}
+	return b.formatSql(sql, args)
+}
+
+// toSqlRaw builds the query leaving placeholders as is.
+func (b *ExplainBuilder) toSqlRaw() (sqlStr string, args []interface{}, err error) {
+	if b.query == nil {
+		err = fmt.Errorf("explain statements must have a query")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+	sql.WriteString("EXPLAIN ")
+
+	switch b.dialect {
+	case PostgresDialect:
+		var options []string
+		if b.analyze {
+			options = append(options, "ANALYZE")
+		}
+		if b.buffers {
+			options = append(options, "BUFFERS")
+		}
+		if b.format != "" {
+			options = append(options, "FORMAT "+b.format)
+		}
+		if len(options) > 0 {
+			fmt.Fprintf(sql, "(%s) ", strings.Join(options, ", "))
+		}
+	case NoDialect, MySQLDialect, SQLiteDialect:
+		if b.buffers {
+			err = errNotSupported("EXPLAIN BUFFERS", b.dialect)
+			return
+		}
+		if b.format != "" && b.dialect != MySQLDialect {
+			err = errNotSupported("EXPLAIN FORMAT", b.dialect)
+			return
+		}
+		if b.analyze {
+			sql.WriteString("ANALYZE ")
+		}
+		if b.format != "" {
+			fmt.Fprintf(sql, "FORMAT=%s ", b.format)
+		}
+	default:
+		err = errNotSupported("EXPLAIN", b.dialect)
+		return
+	}
+
+	querySql, args, err := nestedToSql(b.query)
+	if err != nil {
+		return
+	}
+	sql.WriteString(querySql)
+
+	sqlStr = sql.String()
+	return
+}
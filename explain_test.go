@@ -0,0 +1,70 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	sql, args, err := Select("*").From("users").Where("id = ?", 1).Explain().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXPLAIN SELECT * FROM users WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args, err = Select("*").From("users").Where("id = ?", 1).ExplainAnalyze().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXPLAIN ANALYZE SELECT * FROM users WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestExplainPostgres(t *testing.T) {
+	b := Select("*").From("users").Where("id = ? AND name = ?", 1, "moe").Dialect(PostgresDialect)
+
+	sql, args, err := b.ExplainAnalyze().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXPLAIN (ANALYZE, FORMAT JSON) SELECT * FROM users WHERE id = $1 AND name = $2", sql)
+	assert.Equal(t, []interface{}{1, "moe"}, args)
+
+	sql, _, err = b.Explain().Buffers().Analyze().Format("YAML").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXPLAIN (ANALYZE, BUFFERS, FORMAT YAML) SELECT * FROM users WHERE id = $1 AND name = $2", sql)
+
+	sql, _, err = b.Explain().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXPLAIN SELECT * FROM users WHERE id = $1 AND name = $2", sql)
+}
+
+func TestExplainMySQL(t *testing.T) {
+	sql, _, err := Select("*").From("users").Dialect(MySQLDialect).Explain().Format("JSON").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXPLAIN FORMAT=JSON SELECT * FROM users", sql)
+
+	_, _, err = Select("*").From("users").Dialect(MySQLDialect).Explain().Buffers().ToSql()
+	assert.Error(t, err)
+}
+
+func TestExplainErrors(t *testing.T) {
+	_, _, err := Select("*").From("users").Dialect(SQLServerDialect).Explain().ToSql()
+	assert.EqualError(t, err, "EXPLAIN is not supported by SQL Server dialect")
+
+	_, _, err = Select("*").From("users").Dialect(SQLiteDialect).Explain().Format("JSON").ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select().Explain().ToSql()
+	assert.Error(t, err)
+
+	_, _, err = NewExplainBuilder(StatementBuilder, nil).ToSql()
+	assert.Error(t, err)
+}
+
+func TestExplainRunners(t *testing.T) {
+	db := &DBStub{}
+	b := Select("*").From("users").Where("id = ?", 1).Explain().RunWith(db)
+
+	b.Query()
+	assert.Equal(t, "EXPLAIN SELECT * FROM users WHERE id = ?", db.LastQuerySql)
+
+	_, err := Select("*").From("users").Explain().Query()
+	assert.Equal(t, ErrRunnerNotSet, err)
+}
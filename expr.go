@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -23,24 +24,30 @@ func Expr(sql string, args ...interface{}) expr {
 }
 
 func (e expr) ToSql() (string, []interface{}, error) {
+	return e.toSqlDialect(NoDialect)
+}
+
+func (e expr) toSqlDialect(d Dialect) (string, []interface{}, error) {
 	if !hasSqlizer(e.args) {
 		return e.sql, e.args, nil
 	}
 
 	args := make([]interface{}, 0, len(e.args))
-	sql, err := replacePlaceholders(e.sql, func(buf *bytes.Buffer, i int) error {
+	// escaped question marks are kept, they are unescaped along with placeholders
+	// replacement of the whole statement
+	sql, err := scanPlaceholders(e.sql, "??", func(buf *bytes.Buffer, i int) error {
 		if i > len(e.args) {
 			buf.WriteRune('?')
 			return nil
 		}
 		switch arg := e.args[i-1].(type) {
 		case Sqlizer:
-			sql, vs, err := arg.ToSql()
+			sql, vs, err := dialectToSql(arg, d)
 			if err != nil {
 				return err
 			}
 			args = append(args, vs...)
-			fmt.Fprintf(buf, sql)
+			buf.WriteString(sql)
 		default:
 			args = append(args, arg)
 			buf.WriteRune('?')
@@ -63,11 +70,15 @@ func (es exprs) AppendToSql(w io.Writer, sep string, args []interface{}) ([]inte
 				return nil, err
 			}
 		}
-		_, err := io.WriteString(w, e.sql)
+		sql, eArgs, err := e.ToSql()
 		if err != nil {
 			return nil, err
 		}
-		args = append(args, e.args...)
+		_, err = io.WriteString(w, sql)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, eArgs...)
 	}
 	return args, nil
 }
@@ -87,19 +98,128 @@ func Alias(expr Sqlizer, alias string) aliasExpr {
 }
 
 func (e aliasExpr) ToSql() (sql string, args []interface{}, err error) {
-	sql, args, err = e.expr.ToSql()
+	return e.toSqlDialect(NoDialect)
+}
+
+func (e aliasExpr) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
+	sql, args, err = dialectToSql(e.expr, d)
 	if err == nil {
 		sql = fmt.Sprintf("(%s) AS %s", sql, e.alias)
 	}
 	return
 }
 
+// identExpr is an identifier quoted according to dialect of the statement
+type identExpr string
+
+// Ident builds an identifier, e.g. a column or table name, quoted according to
+// dialect of the statement: "order" for PostgreSQL, SQLite and Oracle, `order`
+// for MySQL and [order] for SQL Server. Without dialect ANSI double quotes are used.
+//
+// Dotted names are quoted by parts, "*" is left as is:
+//		.Column(Ident("users.order")) == "users"."order"
+func Ident(name string) identExpr {
+	return identExpr(name)
+}
+
+func (e identExpr) ToSql() (string, []interface{}, error) {
+	return e.toSqlDialect(NoDialect)
+}
+
+func (e identExpr) toSqlDialect(d Dialect) (string, []interface{}, error) {
+	if e == "" {
+		return "", nil, fmt.Errorf("identifier cannot be empty")
+	}
+	parts := strings.Split(string(e), ".")
+	for i, p := range parts {
+		if p != "*" {
+			parts[i] = d.quoteIdent(p)
+		}
+	}
+	return strings.Join(parts, "."), nil, nil
+}
+
+// castExpr converts a value to a type according to dialect of the statement
+type castExpr struct {
+	value interface{}
+	typ   string
+}
+
+// Cast builds a type cast of value, bound as a single arg, or embedded if it is
+// a Sqlizer. It is rendered as "?::typ" for Postgres dialect, or for Dollar
+// placeholders without dialect, and as ANSI "CAST(? AS typ)" otherwise.
+// Ex:
+//		.Where(Eq{"id": Cast(id, "uuid")})
+func Cast(value interface{}, typ string) castExpr {
+	return castExpr{value: value, typ: typ}
+}
+
+func (e castExpr) ToSql() (string, []interface{}, error) {
+	return e.toSqlDialect(NoDialect)
+}
+
+func (e castExpr) toSqlDialect(d Dialect) (string, []interface{}, error) {
+	sql, args, err := valueToSql(e.value, d)
+	if err != nil {
+		return "", nil, err
+	}
+	if d == PostgresDialect {
+		if s, ok := e.value.(Sqlizer); ok && !isStatement(s) {
+			sql = fmt.Sprintf("(%s)", sql)
+		}
+		return fmt.Sprintf("%s::%s", sql, e.typ), args, nil
+	}
+	return fmt.Sprintf("CAST(%s AS %s)", sql, e.typ), args, nil
+}
+
+// existsExpr helps to check existence of rows returned by a subquery
+type existsExpr struct {
+	subquery Sqlizer
+	not      bool
+}
+
+// Exists builds EXISTS predicate for given subquery, useful for correlated checks
+// Ex:
+//		.Where(Exists(Select("1").From("orders").Where("orders.user_id = users.id")))
+func Exists(subquery Sqlizer) existsExpr {
+	return existsExpr{subquery: subquery}
+}
+
+// NotExists builds NOT EXISTS predicate for given subquery
+func NotExists(subquery Sqlizer) existsExpr {
+	return existsExpr{subquery: subquery, not: true}
+}
+
+func (e existsExpr) ToSql() (sql string, args []interface{}, err error) {
+	if e.subquery == nil {
+		return "", nil, fmt.Errorf("exists predicate requires a subquery")
+	}
+	sql, args, err = nestedToSql(e.subquery)
+	if err != nil {
+		return "", nil, err
+	}
+	if e.not {
+		sql = fmt.Sprintf("NOT EXISTS (%s)", sql)
+	} else {
+		sql = fmt.Sprintf("EXISTS (%s)", sql)
+	}
+	return
+}
+
 // Eq is syntactic sugar for use with Where/Having/Set methods.
 // Ex:
 //     .Where(Eq{"id": 1})
+//
+// Sqlizer values are embedded into the expression: statement builders as
+// subqueries, e.g. Eq{"id": Select("id").From("t")} is "id IN (SELECT id FROM t)",
+// other Sqlizers as is, e.g. Eq{"a.id": Expr("b.a_id")} is "a.id = b.a_id".
+// A database/sql.NamedArg is bound as a single arg whatever it holds, see Named.
+// Nil values, including nil pointers like (*time.Time)(nil), are "IS NULL".
+//
+// Conditions of several keys are ANDed together in alphabetical order of keys.
 type Eq map[string]interface{}
 
-func (eq Eq) toSql(useNotOpr bool) (sql string, args []interface{}, err error) {
+func (eq Eq) toSql(d Dialect, useNotOpr bool) (sql string, args []interface{}, err error) {
 	var (
 		exprs      []string
 		equalOpr   = "="
@@ -115,10 +235,29 @@ func (eq Eq) toSql(useNotOpr bool) (sql string, args []interface{}, err error) {
 		inEmptyExpr = "(1=1)" // Portable TRUE
 	}
 
-	for key, val := range eq {
+	for _, key := range sortedKeys(eq) {
+		val := eq[key]
 		expr := ""
+		// a typed nil pointer is NULL as well, as database/sql binds it
+		if isNil(val) {
+			val = nil
+		}
 
 		switch v := val.(type) {
+		case Sqlizer:
+			var valSql string
+			var valArgs []interface{}
+			if valSql, valArgs, err = dialectToSql(v, d); err != nil {
+				return
+			}
+			if isStatement(v) {
+				expr = fmt.Sprintf("%s %s (%s)", key, inOpr, valSql)
+			} else {
+				expr = fmt.Sprintf("%s %s %s", key, equalOpr, valSql)
+			}
+			exprs = append(exprs, expr)
+			args = append(args, valArgs...)
+			continue
 		case driver.Valuer:
 			if val, err = v.Value(); err != nil {
 				return
@@ -154,7 +293,11 @@ func (eq Eq) toSql(useNotOpr bool) (sql string, args []interface{}, err error) {
 
 // ToSql builds the query into a SQL string and bound args.
 func (eq Eq) ToSql() (sql string, args []interface{}, err error) {
-	return eq.toSql(false)
+	return eq.toSqlDialect(NoDialect)
+}
+
+func (eq Eq) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
+	return eq.toSql(d, false)
 }
 
 // NotEq is syntactic sugar for use with Where/Having/Set methods.
@@ -164,15 +307,72 @@ type NotEq Eq
 
 // ToSql builds the query into a SQL string and bound args.
 func (neq NotEq) ToSql() (sql string, args []interface{}, err error) {
-	return Eq(neq).toSql(true)
+	return neq.toSqlDialect(NoDialect)
+}
+
+func (neq NotEq) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
+	return Eq(neq).toSql(d, true)
+}
+
+// inChunksExpr is an IN predicate split into lists of limited size
+type inChunksExpr struct {
+	column string
+	values interface{}
+	size   int
+}
+
+// InChunks builds IN predicate for a big list of values, with no more than size
+// placeholders in each IN list, so it could be kept under limits of databases:
+//     InChunks("id", []int{1, 2, 3}, 2) == "(id IN (?,?) OR id IN (?))"
+//
+// Empty list is always false: "(1=0)".
+func InChunks(column string, values interface{}, size int) Sqlizer {
+	return inChunksExpr{column: column, values: values, size: size}
+}
+
+func (e inChunksExpr) ToSql() (sql string, args []interface{}, err error) {
+	if e.size <= 0 {
+		err = fmt.Errorf("chunk size must be positive, got %d", e.size)
+		return
+	}
+	if !isListType(e.values) {
+		err = fmt.Errorf("expected array or slice of values, not %T", e.values)
+		return
+	}
+
+	v := reflect.ValueOf(e.values)
+	if v.Len() == 0 {
+		return "(1=0)", []interface{}{}, nil
+	}
+
+	args = make([]interface{}, 0, v.Len())
+	var exprs []string
+	for start := 0; start < v.Len(); start += e.size {
+		end := start + e.size
+		if end > v.Len() {
+			end = v.Len()
+		}
+		for i := start; i < end; i++ {
+			args = append(args, v.Index(i).Interface())
+		}
+		exprs = append(exprs, fmt.Sprintf("%s IN (%s)", e.column, Placeholders(end-start)))
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], args, nil
+	}
+	return fmt.Sprintf("(%s)", strings.Join(exprs, " OR ")), args, nil
 }
 
 // Lt is syntactic sugar for use with Where/Having/Set methods.
 // Ex:
 //     .Where(Lt{"id": 1})
+//
+// Sqlizer values are embedded into the expression, statement builders as scalar
+// subqueries: Lt{"price": Select("AVG(price)").From("t")} is "price < (SELECT AVG(price) FROM t)".
 type Lt map[string]interface{}
 
-func (lt Lt) toSql(opposite, orEq bool) (sql string, args []interface{}, err error) {
+func (lt Lt) toSql(d Dialect, opposite, orEq bool) (sql string, args []interface{}, err error) {
 	var (
 		exprs []string
 		opr   string = "<"
@@ -186,10 +386,23 @@ func (lt Lt) toSql(opposite, orEq bool) (sql string, args []interface{}, err err
 		opr = fmt.Sprintf("%s%s", opr, "=")
 	}
 
-	for key, val := range lt {
+	for _, key := range sortedKeys(lt) {
+		val := lt[key]
 		expr := ""
 
 		switch v := val.(type) {
+		case Sqlizer:
+			var valSql string
+			var valArgs []interface{}
+			if valSql, valArgs, err = dialectToSql(v, d); err != nil {
+				return
+			}
+			if isStatement(v) {
+				valSql = fmt.Sprintf("(%s)", valSql)
+			}
+			exprs = append(exprs, fmt.Sprintf("%s %s %s", key, opr, valSql))
+			args = append(args, valArgs...)
+			continue
 		case driver.Valuer:
 			if val, err = v.Value(); err != nil {
 				return
@@ -215,7 +428,11 @@ func (lt Lt) toSql(opposite, orEq bool) (sql string, args []interface{}, err err
 }
 
 func (lt Lt) ToSql() (sql string, args []interface{}, err error) {
-	return lt.toSql(false, false)
+	return lt.toSqlDialect(NoDialect)
+}
+
+func (lt Lt) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
+	return lt.toSql(d, false, false)
 }
 
 // LtOrEq is syntactic sugar for use with Where/Having/Set methods.
@@ -224,7 +441,11 @@ func (lt Lt) ToSql() (sql string, args []interface{}, err error) {
 type LtOrEq Lt
 
 func (ltOrEq LtOrEq) ToSql() (sql string, args []interface{}, err error) {
-	return Lt(ltOrEq).toSql(false, true)
+	return ltOrEq.toSqlDialect(NoDialect)
+}
+
+func (ltOrEq LtOrEq) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
+	return Lt(ltOrEq).toSql(d, false, true)
 }
 
 // Gt is syntactic sugar for use with Where/Having/Set methods.
@@ -233,7 +454,11 @@ func (ltOrEq LtOrEq) ToSql() (sql string, args []interface{}, err error) {
 type Gt Lt
 
 func (gt Gt) ToSql() (sql string, args []interface{}, err error) {
-	return Lt(gt).toSql(true, false)
+	return gt.toSqlDialect(NoDialect)
+}
+
+func (gt Gt) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
+	return Lt(gt).toSql(d, true, false)
 }
 
 // GtOrEq is syntactic sugar for use with Where/Having/Set methods.
@@ -242,15 +467,248 @@ func (gt Gt) ToSql() (sql string, args []interface{}, err error) {
 type GtOrEq Lt
 
 func (gtOrEq GtOrEq) ToSql() (sql string, args []interface{}, err error) {
-	return Lt(gtOrEq).toSql(true, true)
+	return gtOrEq.toSqlDialect(NoDialect)
+}
+
+func (gtOrEq GtOrEq) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
+	return Lt(gtOrEq).toSql(d, true, true)
+}
+
+// colCmpExpr compares two columns: "left op right"
+type colCmpExpr struct {
+	left  string
+	op    string
+	right string
+}
+
+func (e colCmpExpr) ToSql() (string, []interface{}, error) {
+	return fmt.Sprintf("%s %s %s", e.left, e.op, e.right), nil, nil
+}
+
+// EqCol compares two columns, the right one is not bound as an arg.
+// Ex:
+//     .Where(EqCol("a.id", "b.a_id")) == "a.id = b.a_id"
+//
+// The same could be done with Eq{"a.id": Expr("b.a_id")}.
+func EqCol(left, right string) Sqlizer {
+	return colCmpExpr{left: left, op: "=", right: right}
+}
+
+// NotEqCol is "left <> right", see EqCol.
+func NotEqCol(left, right string) Sqlizer {
+	return colCmpExpr{left: left, op: "<>", right: right}
+}
+
+// LtCol is "left < right", see EqCol.
+func LtCol(left, right string) Sqlizer {
+	return colCmpExpr{left: left, op: "<", right: right}
+}
+
+// LtOrEqCol is "left <= right", see EqCol.
+func LtOrEqCol(left, right string) Sqlizer {
+	return colCmpExpr{left: left, op: "<=", right: right}
+}
+
+// GtCol is "left > right", see EqCol.
+func GtCol(left, right string) Sqlizer {
+	return colCmpExpr{left: left, op: ">", right: right}
+}
+
+// GtOrEqCol is "left >= right", see EqCol.
+func GtOrEqCol(left, right string) Sqlizer {
+	return colCmpExpr{left: left, op: ">=", right: right}
+}
+
+// ILike is syntactic sugar for case insensitive LIKE, use it with Where/Having methods.
+// Ex:
+//     .Where(ILike{"name": "%moe%"})
+//
+// It is rendered as "name ILIKE ?" for Postgres dialect, or for Dollar placeholders
+// without dialect, and as portable "LOWER(name) LIKE LOWER(?)" otherwise, the
+// pattern is a single arg.
+type ILike map[string]interface{}
+
+func (lk ILike) toSql(d Dialect, not bool) (sql string, args []interface{}, err error) {
+	var exprs []string
+	for _, key := range sortedKeys(lk) {
+		val := lk[key]
+		if v, ok := val.(driver.Valuer); ok {
+			if val, err = v.Value(); err != nil {
+				return
+			}
+		}
+		if val == nil {
+			err = fmt.Errorf("cannot use null with like operators")
+			return
+		}
+		if isListType(val) {
+			err = fmt.Errorf("cannot use array or slice with like operators")
+			return
+		}
+
+		var expr string
+		if d == PostgresDialect {
+			opr := "ILIKE"
+			if not {
+				opr = "NOT ILIKE"
+			}
+			expr = fmt.Sprintf("%s %s ?", key, opr)
+		} else {
+			opr := "LIKE"
+			if not {
+				opr = "NOT LIKE"
+			}
+			expr = fmt.Sprintf("LOWER(%s) %s LOWER(?)", key, opr)
+		}
+		exprs = append(exprs, expr)
+		args = append(args, val)
+	}
+	sql = strings.Join(exprs, " AND ")
+	return
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (lk ILike) ToSql() (sql string, args []interface{}, err error) {
+	return lk.toSqlDialect(NoDialect)
+}
+
+func (lk ILike) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
+	return lk.toSql(d, false)
+}
+
+// NotILike is syntactic sugar for case insensitive NOT LIKE, see ILike.
+// Ex:
+//     .Where(NotILike{"name": "%moe%"})
+type NotILike ILike
+
+// ToSql builds the query into a SQL string and bound args.
+func (nlk NotILike) ToSql() (sql string, args []interface{}, err error) {
+	return nlk.toSqlDialect(NoDialect)
+}
+
+func (nlk NotILike) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
+	return ILike(nlk).toSql(d, true)
+}
+
+// betweenExpr helps to check that column is in a range of values
+type betweenExpr struct {
+	column    string
+	low, high interface{}
+	not       bool
+}
+
+// Between builds "column BETWEEN ? AND ?" predicate with both bounds as args.
+// Sqlizer bounds are embedded into the expression, statement builders as
+// scalar subqueries.
+// Ex:
+//		.Where(Between("created_at", Expr("now() - interval '1 day'"), deadline))
+func Between(column string, low, high interface{}) betweenExpr {
+	return betweenExpr{column: column, low: low, high: high}
+}
+
+// NotBetween builds "column NOT BETWEEN ? AND ?" predicate
+func NotBetween(column string, low, high interface{}) betweenExpr {
+	return betweenExpr{column: column, low: low, high: high, not: true}
+}
+
+func (e betweenExpr) ToSql() (sql string, args []interface{}, err error) {
+	lowSql, args, err := operandToSql(e.low, "between", args)
+	if err != nil {
+		return
+	}
+	highSql, args, err := operandToSql(e.high, "between", args)
+	if err != nil {
+		return
+	}
+
+	opr := "BETWEEN"
+	if e.not {
+		opr = "NOT BETWEEN"
+	}
+	sql = fmt.Sprintf("%s %s %s AND %s", e.column, opr, lowSql, highSql)
+	return
+}
+
+// operandToSql builds a scalar operand of opr, appending its args to args
+func operandToSql(val interface{}, opr string, args []interface{}) (string, []interface{}, error) {
+	switch v := val.(type) {
+	case Sqlizer:
+		sql, valArgs, err := nestedToSql(v)
+		if err != nil {
+			return "", nil, err
+		}
+		if isStatement(v) {
+			sql = fmt.Sprintf("(%s)", sql)
+		}
+		return sql, append(args, valArgs...), nil
+	case driver.Valuer:
+		var err error
+		if val, err = v.Value(); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if val == nil {
+		return "", nil, fmt.Errorf("cannot use null with %s operator", opr)
+	}
+	if isListType(val) {
+		return "", nil, fmt.Errorf("cannot use array or slice with %s operator", opr)
+	}
+	return "?", append(args, val), nil
+}
+
+// Range is syntactic sugar for an inclusive range filter, use it with
+// Where/Having methods. Nil bounds, including nil pointers, are omitted:
+//     .Where(Range{Column: "created", From: &since, To: nil}) == "created >= ?"
+//
+// Range without bounds is always true: "(1=1)".
+type Range struct {
+	Column string
+	From   interface{}
+	To     interface{}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (r Range) ToSql() (sql string, args []interface{}, err error) {
+	var exprs []string
+	for _, bound := range []struct {
+		opr string
+		val interface{}
+	}{{">=", r.From}, {"<=", r.To}} {
+		if isNil(bound.val) {
+			continue
+		}
+		var valSql string
+		if valSql, args, err = operandToSql(bound.val, bound.opr, args); err != nil {
+			return
+		}
+		exprs = append(exprs, fmt.Sprintf("%s %s %s", r.Column, bound.opr, valSql))
+	}
+	if len(exprs) == 0 {
+		return "(1=1)", nil, nil
+	}
+	sql = strings.Join(exprs, " AND ")
+	return
+}
+
+// isNil tells whether val is nil or a nil pointer
+func isNil(val interface{}) bool {
+	if val == nil {
+		return true
+	}
+	v := reflect.ValueOf(val)
+	return v.Kind() == reflect.Ptr && v.IsNil()
 }
 
 type conj []Sqlizer
 
-func (c conj) join(sep string) (sql string, args []interface{}, err error) {
+// join glues non-empty parts with sep. Each conjunction is wrapped in parentheses,
+// so nesting keeps the precedence; empty one is rendered as given default,
+// e.g. "(1=1)" for AND, so it doesn't change the meaning of enclosing one.
+func (c conj) join(d Dialect, sep, empty string) (sql string, args []interface{}, err error) {
 	var sqlParts []string
 	for _, sqlizer := range c {
-		partSql, partArgs, err := sqlizer.ToSql()
+		partSql, partArgs, err := dialectToSql(sqlizer, d)
 		if err != nil {
 			return "", nil, err
 		}
@@ -261,6 +719,8 @@ func (c conj) join(sep string) (sql string, args []interface{}, err error) {
 	}
 	if len(sqlParts) > 0 {
 		sql = fmt.Sprintf("(%s)", strings.Join(sqlParts, sep))
+	} else {
+		sql = empty
 	}
 	return
 }
@@ -268,21 +728,44 @@ func (c conj) join(sep string) (sql string, args []interface{}, err error) {
 // And is syntactic sugar that glues where/having parts with AND clause
 // Ex:
 //     .Where(And{Expr("a > ?", 15), Expr("b < ?", 20), Expr("c is TRUE")})
+//
+// Empty And is always true: "(1=1)".
 type And conj
 
 // ToSql builds the query into a SQL string and bound args.
 func (a And) ToSql() (string, []interface{}, error) {
-	return conj(a).join(" AND ")
+	return a.toSqlDialect(NoDialect)
+}
+
+func (a And) toSqlDialect(d Dialect) (string, []interface{}, error) {
+	return conj(a).join(d, " AND ", "(1=1)")
 }
 
 // Or is syntactic sugar that glues where/having parts with OR clause
 // Ex:
 //     .Where(Or{Expr("a > ?", 15), Expr("b < ?", 20), Expr("c is TRUE")})
+//
+// Empty Or is always false: "(1=0)".
 type Or conj
 
 // ToSql builds the query into a SQL string and bound args.
 func (o Or) ToSql() (string, []interface{}, error) {
-	return conj(o).join(" OR ")
+	return o.toSqlDialect(NoDialect)
+}
+
+func (o Or) toSqlDialect(d Dialect) (string, []interface{}, error) {
+	return conj(o).join(d, " OR ", "(1=0)")
+}
+
+// sortedKeys returns keys of m in alphabetical order, so expressions built from
+// maps are the same each time
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 func isListType(val interface{}) bool {
@@ -293,6 +776,13 @@ func isListType(val interface{}) bool {
 	return valVal.Kind() == reflect.Array || valVal.Kind() == reflect.Slice
 }
 
+// isStatement tells whether s is a statement builder, which has to be
+// parenthesized to be used as a subquery
+func isStatement(s Sqlizer) bool {
+	_, ok := s.(rawSqlizer)
+	return ok
+}
+
 func hasSqlizer(args []interface{}) bool {
 	for _, arg := range args {
 		_, ok := arg.(Sqlizer)
@@ -3,6 +3,7 @@ package sqrl
 import (
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -79,6 +80,60 @@ func TestNotEqInEmptyToSql(t *testing.T) {
 	assert.Equal(t, expectedArgs, args)
 }
 
+func TestColumnComparisons(t *testing.T) {
+	tests := []struct {
+		s   Sqlizer
+		sql string
+	}{
+		{EqCol("a.x", "b.y"), "a.x = b.y"},
+		{NotEqCol("a.x", "b.y"), "a.x <> b.y"},
+		{LtCol("a.x", "b.y"), "a.x < b.y"},
+		{LtOrEqCol("a.x", "b.y"), "a.x <= b.y"},
+		{GtCol("a.x", "b.y"), "a.x > b.y"},
+		{GtOrEqCol("a.x", "b.y"), "a.x >= b.y"},
+	}
+	for _, test := range tests {
+		sql, args, err := test.s.ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, test.sql, sql)
+		assert.Empty(t, args)
+	}
+
+	sql, args, err := Select("*").From("a").Join("b ON b.id = a.b_id").
+		Where(GtCol("a.updated_at", "b.created_at")).
+		Where(Eq{"a.kind": 1}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM a JOIN b ON b.id = a.b_id WHERE a.updated_at > b.created_at AND a.kind = ?", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args, err = Gt{"a.x": Expr("b.y")}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "a.x > b.y", sql)
+	assert.Empty(t, args)
+}
+
+func TestEqNilPointerToSql(t *testing.T) {
+	var deletedAt *time.Time
+	var valuer *sql.NullString
+	zero := time.Time{}
+
+	sql, args, err := Eq{"a": nil, "b": deletedAt, "c": &zero}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "a IS NULL AND b IS NULL AND c = ?", sql)
+	assert.Equal(t, []interface{}{&zero}, args)
+
+	sql, args, err = NotEq{"a": nil, "b": deletedAt, "c": &zero}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "a IS NOT NULL AND b IS NOT NULL AND c <> ?", sql)
+	assert.Equal(t, []interface{}{&zero}, args)
+
+	sql, args, err = Eq{"d": valuer}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "d IS NULL", sql)
+	assert.Empty(t, args)
+}
+
 func TestEqBytesToSql(t *testing.T) {
 	b := Eq{"id": []byte("test")}
 	sql, args, err := b.ToSql()
@@ -212,3 +267,434 @@ func TestExprSqlizer(t *testing.T) {
 		assert.Equal(t, []interface{}{42, 42}, args)
 	}
 }
+
+func TestEqInSliceSizes(t *testing.T) {
+	tests := []struct {
+		pred Sqlizer
+		sql  string
+		args []interface{}
+	}{
+		{Eq{"id": []int{}}, "(1=0)", []interface{}{}},
+		{Eq{"id": []int{1}}, "id IN (?)", []interface{}{1}},
+		{Eq{"id": []int{1, 2}}, "id IN (?,?)", []interface{}{1, 2}},
+		{NotEq{"id": []string{}}, "(1=1)", []interface{}{}},
+		{NotEq{"id": []string{"a"}}, "id NOT IN (?)", []interface{}{"a"}},
+		{NotEq{"id": [2]string{"a", "b"}}, "id NOT IN (?,?)", []interface{}{"a", "b"}},
+	}
+
+	for _, test := range tests {
+		sql, args, err := test.pred.ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, test.sql, sql)
+		assert.Equal(t, test.args, args)
+	}
+}
+
+func TestEqInEmptyWhere(t *testing.T) {
+	sql, args, err := Select("*").
+		From("users").
+		Where(Eq{"id": []int{}}).
+		Where(NotEq{"role": []string{}}).
+		Where("active = ?", true).
+		PlaceholderFormat(Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE (1=0) AND (1=1) AND active = $1", sql)
+	assert.Equal(t, []interface{}{true}, args)
+}
+
+func TestEqSubquery(t *testing.T) {
+	sub := Select("user_id").From("orders").Where("total > ?", 100)
+
+	sql, args, err := Eq{"id": sub}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "id IN (SELECT user_id FROM orders WHERE total > ?)", sql)
+	assert.Equal(t, []interface{}{100}, args)
+
+	sql, args, err = NotEq{"id": sub}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "id NOT IN (SELECT user_id FROM orders WHERE total > ?)", sql)
+	assert.Equal(t, []interface{}{100}, args)
+
+	sql, args, err = Eq{"a.id": Expr("b.a_id")}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "a.id = b.a_id", sql)
+	assert.Empty(t, args)
+
+	_, _, err = Eq{"id": Select()}.ToSql()
+	assert.Error(t, err)
+}
+
+func TestLtSubquery(t *testing.T) {
+	sub := Select("AVG(price)").From("items").Where("category = ?", "books")
+
+	sql, args, err := Lt{"price": sub}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "price < (SELECT AVG(price) FROM items WHERE category = ?)", sql)
+	assert.Equal(t, []interface{}{"books"}, args)
+
+	sql, args, err = GtOrEq{"created_at": Expr("NOW() - ?::interval", "1 day")}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "created_at >= NOW() - ?::interval", sql)
+	assert.Equal(t, []interface{}{"1 day"}, args)
+
+	_, _, err = Gt{"price": Select()}.ToSql()
+	assert.Error(t, err)
+}
+
+func TestEqSubqueryPlaceholders(t *testing.T) {
+	sub := Select("user_id").From("orders").Where("total > ?", 100).PlaceholderFormat(Dollar)
+	sql, args, err := Select("*").
+		From("users").
+		Where("active = ?", true).
+		Where(Eq{"id": sub}).
+		Where(Gt{"age": Select("MIN(age)").From("adults").Where("country = ?", "NL")}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE active = $1 "+
+		"AND id IN (SELECT user_id FROM orders WHERE total > $2) "+
+		"AND age > (SELECT MIN(age) FROM adults WHERE country = $3)", sql)
+	assert.Equal(t, []interface{}{true, 100, "NL"}, args)
+}
+
+func TestExistsToSql(t *testing.T) {
+	sub := Select("1").From("orders").Where("orders.user_id = users.id AND total > ?", 100)
+
+	sql, args, err := Exists(sub).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id AND total > ?)", sql)
+	assert.Equal(t, []interface{}{100}, args)
+
+	sql, args, err = NotExists(sub).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "NOT EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id AND total > ?)", sql)
+	assert.Equal(t, []interface{}{100}, args)
+}
+
+func TestExistsInWhere(t *testing.T) {
+	sql, args, err := Select("*").
+		From("users").
+		Where("active = ?", true).
+		Where(NotExists(Select("1").From("bans").Where("bans.user_id = users.id AND until > ?", "now"))).
+		PlaceholderFormat(Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE active = $1 "+
+		"AND NOT EXISTS (SELECT 1 FROM bans WHERE bans.user_id = users.id AND until > $2)", sql)
+	assert.Equal(t, []interface{}{true, "now"}, args)
+}
+
+func TestExistsErrors(t *testing.T) {
+	_, _, err := Exists(Select()).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select("*").From("users").Where(NotExists(Select())).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Exists(nil).ToSql()
+	assert.Error(t, err)
+}
+
+func TestConjNesting(t *testing.T) {
+	pred := Or{
+		And{Expr("a = ?", 1), Expr("b = ?", 2)},
+		And{
+			Expr("c = ?", 3),
+			Or{Expr("d = ?", 4), And{Expr("e = ?", 5), Expr("f = ?", 6)}},
+		},
+	}
+
+	sql, args, err := pred.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "((a = ? AND b = ?) OR (c = ? AND (d = ? OR (e = ? AND f = ?))))", sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 4, 5, 6}, args)
+}
+
+func TestConjEmpty(t *testing.T) {
+	sql, args, err := And{}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(1=1)", sql)
+	assert.Empty(t, args)
+
+	sql, args, err = Or{}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(1=0)", sql)
+	assert.Empty(t, args)
+
+	sql, args, err = Or{And{}, Expr("x = ?", 1)}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "((1=1) OR x = ?)", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, _, err = Select("*").From("a").Where(And{Or{}, Expr("y")}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM a WHERE ((1=0) AND y)", sql)
+}
+
+func TestBetween(t *testing.T) {
+	sql, args, err := Between("age", 18, 65).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "age BETWEEN ? AND ?", sql)
+	assert.Equal(t, []interface{}{18, 65}, args)
+
+	sql, args, err = NotBetween("age", 18, 65).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "age NOT BETWEEN ? AND ?", sql)
+	assert.Equal(t, []interface{}{18, 65}, args)
+}
+
+func TestBetweenSqlizerBounds(t *testing.T) {
+	sql, args, err := Between("created_at", Expr("now() - ?::interval", "1 day"), "2020-01-01").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "created_at BETWEEN now() - ?::interval AND ?", sql)
+	assert.Equal(t, []interface{}{"1 day", "2020-01-01"}, args)
+
+	sql, args, err = Between("price", 10, Select("MAX(price)").From("items").Where("kind = ?", 2)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "price BETWEEN ? AND (SELECT MAX(price) FROM items WHERE kind = ?)", sql)
+	assert.Equal(t, []interface{}{10, 2}, args)
+}
+
+func TestBetweenInConj(t *testing.T) {
+	sql, args, err := Select("*").From("t").
+		Where(Or{Between("a", 1, 2), And{NotBetween("b", 3, 4), Eq{"c": 5}}}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE (a BETWEEN $1 AND $2 OR (b NOT BETWEEN $3 AND $4 AND c = $5))", sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 4, 5}, args)
+}
+
+func TestBetweenErrors(t *testing.T) {
+	_, _, err := Between("a", nil, 1).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Between("a", 1, []int{2, 3}).ToSql()
+	assert.Error(t, err)
+}
+
+func TestILike(t *testing.T) {
+	sql, args, err := ILike{"name": "%moe%"}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "LOWER(name) LIKE LOWER(?)", sql)
+	assert.Equal(t, []interface{}{"%moe%"}, args)
+
+	sql, args, err = NotILike{"name": "%moe%"}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "LOWER(name) NOT LIKE LOWER(?)", sql)
+	assert.Equal(t, []interface{}{"%moe%"}, args)
+
+	_, _, err = ILike{"name": nil}.ToSql()
+	assert.Error(t, err)
+}
+
+func TestILikeDialects(t *testing.T) {
+	pred := And{ILike{"name": "%moe%"}, NotILike{"email": "%@example.com"}}
+
+	sql, args, err := Select("*").From("users").Where(pred).Dialect(PostgresDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE (name ILIKE $1 AND email NOT ILIKE $2)", sql)
+	assert.Equal(t, []interface{}{"%moe%", "%@example.com"}, args)
+
+	sql, args, err = Select("*").From("users").Where(pred).Dialect(MySQLDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE (LOWER(name) LIKE LOWER(?) AND LOWER(email) NOT LIKE LOWER(?))", sql)
+	assert.Equal(t, []interface{}{"%moe%", "%@example.com"}, args)
+
+	sql, _, err = Delete("users").Where(ILike{"name": "%moe%"}).Dialect(PostgresDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE name ILIKE $1", sql)
+
+	sql, _, err = Update("users").Set("active", false).Where(Or{ILike{"name": "%moe%"}}).Dialect(SQLiteDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET active = ? WHERE (LOWER(name) LIKE LOWER(?))", sql)
+}
+
+func TestRange(t *testing.T) {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	var none *time.Time
+
+	sql, args, err := Range{Column: "created", From: &from, To: &to}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "created >= ? AND created <= ?", sql)
+	assert.Equal(t, []interface{}{&from, &to}, args)
+
+	sql, args, err = Range{Column: "created", From: &from, To: none}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "created >= ?", sql)
+	assert.Equal(t, []interface{}{&from}, args)
+
+	sql, args, err = Range{Column: "created", To: 10}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "created <= ?", sql)
+	assert.Equal(t, []interface{}{10}, args)
+
+	sql, args, err = Range{Column: "created", From: none}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(1=1)", sql)
+	assert.Empty(t, args)
+}
+
+func TestRangeInWhere(t *testing.T) {
+	sql, args, err := Select("*").From("t").
+		Where(Range{Column: "a", From: 1, To: Expr("b + ?", 2)}).
+		Where(Or{Range{Column: "c", To: 3}, Range{Column: "d"}}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE a >= $1 AND a <= b + $2 AND (c <= $3 OR (1=1))", sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+
+	_, _, err = Range{Column: "a", From: []int{1}}.ToSql()
+	assert.Error(t, err)
+}
+
+func TestCast(t *testing.T) {
+	sql, args, err := Cast("42", "integer").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CAST(? AS integer)", sql)
+	assert.Equal(t, []interface{}{"42"}, args)
+
+	sql, args, err = Cast(Expr("a + ?", 1), "text").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CAST(a + ? AS text)", sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestCastPostgres(t *testing.T) {
+	id := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	sql, args, err := Select("id").
+		Column(Alias(Cast(Expr("data->>?", "n"), "int"), "n")).
+		From("t").
+		Where(Eq{"id": Cast(id, "uuid")}).
+		Where("data @> ?", Cast(`{"a":1}`, "jsonb")).
+		Where(Gt{"n": Cast(Select("MIN(n)").From("u"), "int")}).
+		Dialect(PostgresDialect).
+		ToSql()
+
+	assert.NoError(t, err)
+	expectedSql := "SELECT id, ((data->>$1)::int) AS n FROM t " +
+		"WHERE id = $2::uuid AND data @> $3::jsonb AND n > (SELECT MIN(n) FROM u)::int"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"n", id, `{"a":1}`}, args)
+}
+
+func TestDollarInfersPostgres(t *testing.T) {
+	sql, args, err := Select("id").From("t").
+		Where(ILike{"n": "%a%"}).
+		Where(Eq{"id": Cast("x", "uuid")}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE n ILIKE $1 AND id = $2::uuid", sql)
+	assert.Equal(t, []interface{}{"%a%", "x"}, args)
+
+	sql, _, err = Update("t").Set("id", Cast("x", "uuid")).Where(NotILike{"n": "%a%"}).
+		PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET id = $1::uuid WHERE n NOT ILIKE $2", sql)
+
+	sql, _, err = Select("id").From("t").Where(ILike{"n": "%a%"}).Where(Eq{"id": Cast("x", "uuid")}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE LOWER(n) LIKE LOWER(?) AND id = CAST(? AS uuid)", sql)
+}
+
+func TestCastSet(t *testing.T) {
+	b := Update("t").Set("data", Cast(`{}`, "json")).Where("id = ?", 1)
+
+	sql, args, err := b.Dialect(PostgresDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET data = $1::json WHERE id = $2", sql)
+	assert.Equal(t, []interface{}{`{}`, 1}, args)
+
+	sql, _, err = b.Dialect(MySQLDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET data = CAST(? AS json) WHERE id = ?", sql)
+
+	sql, _, err = Insert("t").Columns("data").Values(Cast(`{}`, "json")).Dialect(PostgresDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (data) VALUES ($1::json)", sql)
+}
+
+func TestInChunks(t *testing.T) {
+	ids := func(n int) []int {
+		result := make([]int, n)
+		for i := range result {
+			result[i] = i + 1
+		}
+		return result
+	}
+
+	tests := []struct {
+		n   int
+		sql string
+	}{
+		{1, "id IN (?)"},
+		{2, "id IN (?,?)"},
+		{3, "id IN (?,?,?)"},
+		{4, "(id IN (?,?,?) OR id IN (?))"},
+		{6, "(id IN (?,?,?) OR id IN (?,?,?))"},
+		{7, "(id IN (?,?,?) OR id IN (?,?,?) OR id IN (?))"},
+	}
+
+	for _, test := range tests {
+		values := ids(test.n)
+		sql, args, err := InChunks("id", values, 3).ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, test.sql, sql, "%d values", test.n)
+
+		expectedArgs := make([]interface{}, len(values))
+		for i, v := range values {
+			expectedArgs[i] = v
+		}
+		assert.Equal(t, expectedArgs, args, "%d values", test.n)
+	}
+}
+
+func TestInChunksEdgeCases(t *testing.T) {
+	sql, args, err := InChunks("id", []string{}, 3).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(1=0)", sql)
+	assert.Empty(t, args)
+
+	_, _, err = InChunks("id", []int{1}, 0).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = InChunks("id", 1, 3).ToSql()
+	assert.Error(t, err)
+
+	sql, args, err = Select("*").From("t").
+		Where("a = ?", 0).
+		Where(InChunks("id", [3]int{1, 2, 3}, 2)).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE a = $1 AND (id IN ($2,$3) OR id IN ($4))", sql)
+	assert.Equal(t, []interface{}{0, 1, 2, 3}, args)
+}
+
+func TestMapExprsDeterministic(t *testing.T) {
+	m := map[string]interface{}{"d": 4, "b": 2, "a": nil, "c": []int{3, 5}, "e": 6}
+	for i := 0; i < 100; i++ {
+		sql, args, err := Eq(m).ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, "a IS NULL AND b = ? AND c IN (?,?) AND d = ? AND e = ?", sql)
+		assert.Equal(t, []interface{}{2, 3, 5, 4, 6}, args)
+
+		sql, args, err = NotEq(m).ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, "a IS NOT NULL AND b <> ? AND c NOT IN (?,?) AND d <> ? AND e <> ?", sql)
+		assert.Equal(t, []interface{}{2, 3, 5, 4, 6}, args)
+
+		sql, args, err = Lt{"z": 1, "y": 2, "x": 3}.ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, "x < ? AND y < ? AND z < ?", sql)
+		assert.Equal(t, []interface{}{3, 2, 1}, args)
+	}
+}
@@ -0,0 +1,93 @@
+package sqrl
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// funcExpr is a call of SQL function: "NAME(args)"
+type funcExpr struct {
+	name     string
+	distinct bool
+	args     []Sqlizer
+}
+
+// Func builds a call of SQL function name with given arguments. Sqlizers are
+// embedded and any other value, strings included, is bound as an argument, so
+// user input is never written into SQL. Columns and other SQL have to be given
+// as Ident or Expr:
+//
+//	Func("COALESCE", Ident("nickname"), "n/a") == `COALESCE("nickname", ?)`
+//	Func("LOWER", Expr("name")) == "LOWER(name)"
+//
+// Calls could be nested and aliased:
+//
+//	.Column(Alias(Coalesce(Sum(Expr("amount")), 0), "total"))
+func Func(name string, args ...interface{}) Sqlizer {
+	return newFuncExpr(name, false, args)
+}
+
+func newFuncExpr(name string, distinct bool, args []interface{}) funcExpr {
+	parts := make([]Sqlizer, len(args))
+	for i, arg := range args {
+		if s, ok := arg.(Sqlizer); ok {
+			parts[i] = s
+		} else {
+			parts[i] = Expr("?", arg)
+		}
+	}
+	return funcExpr{name: name, distinct: distinct, args: parts}
+}
+
+func (e funcExpr) ToSql() (string, []interface{}, error) {
+	return e.toSqlDialect(NoDialect)
+}
+
+func (e funcExpr) toSqlDialect(d Dialect) (string, []interface{}, error) {
+	if len(e.args) == 0 {
+		return "", nil, fmt.Errorf("%s requires at least one argument", e.name)
+	}
+
+	sql := &bytes.Buffer{}
+	sql.WriteString(e.name)
+	sql.WriteString("(")
+	if e.distinct {
+		sql.WriteString("DISTINCT ")
+	}
+	args, err := appendToSql(e.args, sql, ", ", d, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	sql.WriteString(")")
+	return sql.String(), args, nil
+}
+
+// Coalesce builds "COALESCE(args)", see Func about arguments.
+func Coalesce(args ...interface{}) Sqlizer {
+	return newFuncExpr("COALESCE", false, args)
+}
+
+// Count builds "COUNT(expr)" or "COUNT(DISTINCT expr)", see Func about arguments.
+func Count(expr interface{}, distinct bool) Sqlizer {
+	return newFuncExpr("COUNT", distinct, []interface{}{expr})
+}
+
+// Sum builds "SUM(expr)", see Func about arguments.
+func Sum(expr interface{}) Sqlizer {
+	return newFuncExpr("SUM", false, []interface{}{expr})
+}
+
+// Max builds "MAX(expr)", see Func about arguments.
+func Max(expr interface{}) Sqlizer {
+	return newFuncExpr("MAX", false, []interface{}{expr})
+}
+
+// Min builds "MIN(expr)", see Func about arguments.
+func Min(expr interface{}) Sqlizer {
+	return newFuncExpr("MIN", false, []interface{}{expr})
+}
+
+// Avg builds "AVG(expr)", see Func about arguments.
+func Avg(expr interface{}) Sqlizer {
+	return newFuncExpr("AVG", false, []interface{}{expr})
+}
@@ -0,0 +1,73 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuncs(t *testing.T) {
+	tests := []struct {
+		s    Sqlizer
+		sql  string
+		args []interface{}
+	}{
+		{Coalesce(Expr("nickname"), Ident("name"), "n/a"), `COALESCE(nickname, "name", ?)`, []interface{}{"n/a"}},
+		{Coalesce(Expr("score"), 0), "COALESCE(score, ?)", []interface{}{0}},
+		{Count(Expr("*"), false), "COUNT(*)", nil},
+		{Count(Expr("user_id"), true), "COUNT(DISTINCT user_id)", nil},
+		{Sum(Expr("amount")), "SUM(amount)", nil},
+		{Max(Expr("price * ?", 2)), "MAX(price * ?)", []interface{}{2}},
+		{Min(Ident("created")), `MIN("created")`, nil},
+		{Avg(Coalesce(Expr("rating"), 3)), "AVG(COALESCE(rating, ?))", []interface{}{3}},
+		{Func("GREATEST", Expr("a"), 1, Max(Expr("b"))), "GREATEST(a, ?, MAX(b))", []interface{}{1}},
+	}
+
+	for _, test := range tests {
+		sql, args, err := test.s.ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, test.sql, sql)
+		assert.Equal(t, test.args, args)
+	}
+
+	_, _, err := Coalesce().ToSql()
+	assert.Error(t, err)
+}
+
+func TestFuncsInSelect(t *testing.T) {
+	sql, args, err := Select("user_id").
+		Column(Alias(Coalesce(Sum(Expr("amount * ?", 100)), 0), "total")).
+		Column(Alias(Count(Expr("id"), true), "orders")).
+		From("orders").
+		Where("created > ?", "2020-01-01").
+		GroupBy("user_id").
+		Having(Gt{"MAX(amount)": 10}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	expectedSql := "SELECT user_id, (COALESCE(SUM(amount * $1), $2)) AS total, (COUNT(DISTINCT id)) AS orders " +
+		"FROM orders WHERE created > $3 GROUP BY user_id HAVING MAX(amount) > $4"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{100, 0, "2020-01-01", 10}, args)
+}
+
+func TestFuncsBindStrings(t *testing.T) {
+	input := "x') OR ('1'='1"
+	sql, args, err := Select("id").From("users").Where(Eq{"name": Func("LOWER", input)}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE name = LOWER(?)", sql)
+	assert.Equal(t, []interface{}{input}, args)
+
+	sql, args, err = Coalesce(Expr("nickname"), "default").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "COALESCE(nickname, ?)", sql)
+	assert.Equal(t, []interface{}{"default"}, args)
+}
+
+func TestFuncsDialect(t *testing.T) {
+	sql, args, err := Select().Column(Coalesce(Cast("1", "int"), 0)).Dialect(PostgresDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COALESCE($1::int, $2)", sql)
+	assert.Equal(t, []interface{}{"1", 0}, args)
+}
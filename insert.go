@@ -15,14 +15,29 @@ type InsertBuilder struct {
 	StatementBuilderType
 
 	returning
+	with
 
 	prefixes exprs
 	options  []string
 	into     string
 	columns  []string
 	values   [][]interface{}
+	rowFuncs []func() ([]interface{}, bool)
+	setMap   bool
 	suffixes exprs
 	iselect  *SelectBuilder
+
+	conflict *onConflict
+
+	err error
+}
+
+// onConflict describes "ON CONFLICT" (PostgreSQL) or "ON DUPLICATE KEY" (MySQL)
+// clause of INSERT statement
+type onConflict struct {
+	columns   []string
+	doNothing bool
+	updates   []setClause
 }
 
 // NewInsertBuilder creates new instance of InsertBuilder
@@ -36,9 +51,16 @@ func (b *InsertBuilder) RunWith(runner BaseRunner) *InsertBuilder {
 	return b
 }
 
+// WithContext sets ctx to be used by Exec, Query, QueryRow and Scan, which do not take a context.
+// See SelectBuilder.WithContext.
+func (b *InsertBuilder) WithContext(ctx context.Context) *InsertBuilder {
+	b.ctx = ctx
+	return b
+}
+
 // Exec builds and Execs the query with the Runner set by RunWith.
 func (b *InsertBuilder) Exec() (sql.Result, error) {
-	return b.ExecContext(context.Background())
+	return b.ExecContext(b.runContext())
 }
 
 // ExecContext builds and Execs the query with the Runner set by RunWith using given context.
@@ -51,7 +73,7 @@ func (b *InsertBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
 
 // Query builds and Querys the query with the Runner set by RunWith.
 func (b *InsertBuilder) Query() (*sql.Rows, error) {
-	return b.QueryContext(context.Background())
+	return b.QueryContext(b.runContext())
 }
 
 // QueryContext builds and runs the query using given context and Query command.
@@ -64,7 +86,7 @@ func (b *InsertBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
 
 // QueryRow builds and QueryRows the query with the Runner set by RunWith.
 func (b *InsertBuilder) QueryRow() RowScanner {
-	return b.QueryRowContext(context.Background())
+	return b.QueryRowContext(b.runContext())
 }
 
 // QueryRowContext builds and runs the query using given context.
@@ -91,8 +113,34 @@ func (b *InsertBuilder) PlaceholderFormat(f PlaceholderFormat) *InsertBuilder {
 	return b
 }
 
+// Dialect sets Dialect (e.g. PostgresDialect or MySQLDialect) for the query.
+// PlaceholderFormat is set to the one used by the dialect.
+func (b *InsertBuilder) Dialect(d Dialect) *InsertBuilder {
+	b.dialect = d
+	b.placeholderFormat = d.PlaceholderFormat()
+	return b
+}
+
 // ToSql builds the query into a SQL string and bound args.
-func (b *InsertBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+func (b *InsertBuilder) ToSql() (string, []interface{}, error) {
+	sql, args, err := b.toSqlRaw()
+	if err != nil {
+		return "", nil, err
+	}
+	return b.formatSql(sql, args)
+}
+
+// MustSql builds the query like ToSql and panics on error, see MustSql.
+func (b *InsertBuilder) MustSql() (string, []interface{}) {
+	return MustSql(b)
+}
+
+// toSqlRaw builds the query leaving placeholders as is.
+func (b *InsertBuilder) toSqlRaw() (sqlStr string, args []interface{}, err error) {
+	if b.err != nil {
+		err = b.err
+		return
+	}
 	if len(b.into) == 0 {
 		err = fmt.Errorf("insert statements must specify a table")
 		return
@@ -101,14 +149,28 @@ func (b *InsertBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 		err = fmt.Errorf("insert statements must have at least one set of values or select clause")
 		return
 	}
+	if len(b.values) > 0 && b.iselect != nil {
+		err = fmt.Errorf("insert statements cannot have both values and select clause")
+		return
+	}
 
 	sql := &bytes.Buffer{}
 
 	if len(b.prefixes) > 0 {
-		args, _ = b.prefixes.AppendToSql(sql, " ", args)
+		args, err = b.prefixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
+	if len(b.ctes) > 0 {
+		args, err = b.with.AppendToSql(sql, args)
+		if err != nil {
+			return
+		}
+	}
+
 	sql.WriteString("INSERT ")
 
 	if len(b.options) > 0 {
@@ -135,8 +197,15 @@ func (b *InsertBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 		return
 	}
 
+	if b.conflict != nil {
+		args, err = b.appendConflictToSQL(sql, args)
+		if err != nil {
+			return
+		}
+	}
+
 	if len(b.returning) > 0 {
-		args, err = b.returning.AppendToSql(sql, args)
+		args, err = b.returning.AppendToSql(sql, b.dialect, args)
 		if err != nil {
 			return
 		}
@@ -144,10 +213,13 @@ func (b *InsertBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = b.suffixes.AppendToSql(sql, " ", args)
+		args, err = b.suffixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 	}
 
-	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	sqlStr = sql.String()
 	return
 }
 
@@ -160,31 +232,12 @@ func (b *InsertBuilder) appendValuesToSQL(w io.Writer, args []interface{}) ([]in
 
 	valuesStrings := make([]string, len(b.values))
 	for r, row := range b.values {
-		valueStrings := make([]string, len(row))
-		for v, val := range row {
-
-			switch typedVal := val.(type) {
-			case expr:
-				valueStrings[v] = typedVal.sql
-				args = append(args, typedVal.args...)
-			case Sqlizer:
-				var valSql string
-				var valArgs []interface{}
-				var err error
-
-				valSql, valArgs, err = typedVal.ToSql()
-				if err != nil {
-					return nil, err
-				}
-
-				valueStrings[v] = valSql
-				args = append(args, valArgs...)
-			default:
-				valueStrings[v] = "?"
-				args = append(args, val)
-			}
+		rowSql, rowArgs, err := valuesRowToSql(row, b.exprDialect())
+		if err != nil {
+			return nil, err
 		}
-		valuesStrings[r] = fmt.Sprintf("(%s)", strings.Join(valueStrings, ","))
+		valuesStrings[r] = rowSql
+		args = append(args, rowArgs...)
 	}
 
 	io.WriteString(w, strings.Join(valuesStrings, ","))
@@ -192,12 +245,27 @@ func (b *InsertBuilder) appendValuesToSQL(w io.Writer, args []interface{}) ([]in
 	return args, nil
 }
 
+// valuesRowToSql builds a single "(...)" row of VALUES clause
+func valuesRowToSql(row []interface{}, d Dialect) (string, []interface{}, error) {
+	var args []interface{}
+	valueStrings := make([]string, len(row))
+	for v, val := range row {
+		valSql, valArgs, err := valueToSql(val, d)
+		if err != nil {
+			return "", nil, err
+		}
+		valueStrings[v] = valSql
+		args = append(args, valArgs...)
+	}
+	return fmt.Sprintf("(%s)", strings.Join(valueStrings, ",")), args, nil
+}
+
 func (b *InsertBuilder) appendSelectToSQL(w io.Writer, args []interface{}) ([]interface{}, error) {
 	if b.iselect == nil {
 		return args, errors.New("select clause for insert statements are not set")
 	}
 
-	selectClause, sArgs, err := b.iselect.ToSql()
+	selectClause, sArgs, err := b.iselect.toSqlRaw()
 	if err != nil {
 		return args, err
 	}
@@ -208,7 +276,200 @@ func (b *InsertBuilder) appendSelectToSQL(w io.Writer, args []interface{}) ([]in
 	return args, nil
 }
 
-// Prefix adds an expression to the beginning of the query
+func (b *InsertBuilder) appendConflictToSQL(w io.Writer, args []interface{}) ([]interface{}, error) {
+	onDuplicateKey := false
+	switch b.exprDialect() {
+	case PostgresDialect, SQLiteDialect:
+	case MySQLDialect, NoDialect:
+		// without dialect formats other than Dollar are treated as MySQL
+		onDuplicateKey = true
+	default:
+		return nil, errNotSupported("ON CONFLICT clause", b.dialect)
+	}
+
+	if !onDuplicateKey {
+		io.WriteString(w, " ON CONFLICT")
+		if len(b.conflict.columns) > 0 {
+			io.WriteString(w, " (")
+			io.WriteString(w, strings.Join(b.conflict.columns, ", "))
+			io.WriteString(w, ")")
+		}
+
+		if b.conflict.doNothing || len(b.conflict.updates) == 0 {
+			io.WriteString(w, " DO NOTHING")
+			return args, nil
+		}
+
+		if len(b.conflict.columns) == 0 {
+			return args, errors.New("ON CONFLICT DO UPDATE requires conflict target columns, set them with OnConflict")
+		}
+		io.WriteString(w, " DO UPDATE SET ")
+		return appendSetClauses(b.conflict.updates, w, b.exprDialect(), args)
+	}
+
+	if b.conflict.doNothing || len(b.conflict.updates) == 0 {
+		return args, errors.New("ON DUPLICATE KEY clause requires at least one update, use Options(\"IGNORE\") instead of DoNothing")
+	}
+
+	io.WriteString(w, " ON DUPLICATE KEY UPDATE ")
+	return appendSetClauses(b.conflict.updates, w, b.exprDialect(), args)
+}
+
+// With adds a common table expression to WITH clause of the query:
+// "WITH name AS (query) INSERT ...".
+//
+// The name is written verbatim, so column list could be given as well, e.g. "t(a, b)".
+func (b *InsertBuilder) With(name string, query Sqlizer) *InsertBuilder {
+	b.with.With(name, query)
+	return b
+}
+
+// WithRecursive adds a common table expression to WITH clause of the query,
+// making the clause "WITH RECURSIVE".
+func (b *InsertBuilder) WithRecursive(name string, query Sqlizer) *InsertBuilder {
+	b.with.WithRecursive(name, query)
+	return b
+}
+
+// ToSqlBatches splits rows of VALUES clause into several INSERT statements, so
+// that none of them has more than maxParams bound args, e.g. 65535 for PostgreSQL.
+//
+// Rows are kept whole and in order; args of other clauses are repeated in each
+// statement. An error is returned if a single row does not fit into maxParams.
+func (b *InsertBuilder) ToSqlBatches(maxParams int) ([]string, [][]interface{}, error) {
+	if maxParams <= 0 {
+		return nil, nil, fmt.Errorf("max params must be positive, got %d", maxParams)
+	}
+	if b.iselect != nil {
+		return nil, nil, errors.New("insert statements with select clause cannot be split into batches")
+	}
+
+	_, args, err := b.toSqlRaw()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rowParams := make([]int, len(b.values))
+	valuesParams := 0
+	for i, row := range b.values {
+		_, rowArgs, err := valuesRowToSql(row, b.exprDialect())
+		if err != nil {
+			return nil, nil, err
+		}
+		rowParams[i] = len(rowArgs)
+		valuesParams += len(rowArgs)
+	}
+	fixedParams := len(args) - valuesParams
+
+	var batches [][][]interface{}
+	start, params := 0, fixedParams
+	for i, n := range rowParams {
+		if fixedParams+n > maxParams {
+			return nil, nil, fmt.Errorf("row %d needs %d params, which exceeds the limit of %d", i, fixedParams+n, maxParams)
+		}
+		if params+n > maxParams {
+			batches = append(batches, b.values[start:i])
+			start, params = i, fixedParams
+		}
+		params += n
+	}
+	batches = append(batches, b.values[start:])
+
+	sqls := make([]string, len(batches))
+	batchArgs := make([][]interface{}, len(batches))
+	for i, values := range batches {
+		batch := *b
+		batch.values = values
+		sqls[i], batchArgs[i], err = batch.ToSql()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return sqls, batchArgs, nil
+}
+
+// AddFunc adds a generator of rows for ExecStream. It is called for each next
+// row until it returns false. Only ExecStream pulls rows from generators,
+// ToSql and ToSqlBatches ignore them.
+func (b *InsertBuilder) AddFunc(next func() ([]interface{}, bool)) *InsertBuilder {
+	b.rowFuncs = append(b.rowFuncs, next)
+	return b
+}
+
+// ExecStream Execs the query with runner in batches of up to batchSize rows,
+// so rows of generators added by AddFunc are not kept in memory all at once.
+// Rows added by Values are sent first, followed by rows of each generator in
+// the order they were added.
+//
+// The context is checked before rows of each batch are pulled, so no rows are
+// pulled after it is done. If it is done while a batch is being pulled, the
+// batch is not executed and the error wraps ctx.Err() with the number of
+// pulled rows that were not inserted. The total number of rows affected by the
+// executed batches is returned, also along with an error.
+func (b *InsertBuilder) ExecStream(ctx context.Context, runner ExecerContext, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("batch size must be positive, got %d", batchSize)
+	}
+	if b.iselect != nil {
+		return 0, errors.New("insert statements with select clause cannot be streamed")
+	}
+
+	pending := b.values
+	funcs := b.rowFuncs
+	next := func() ([]interface{}, bool) {
+		if len(pending) > 0 {
+			row := pending[0]
+			pending = pending[1:]
+			return row, true
+		}
+		for len(funcs) > 0 {
+			if row, ok := funcs[0](); ok {
+				return row, true
+			}
+			funcs = funcs[1:]
+		}
+		return nil, false
+	}
+
+	var total int64
+	rows := make([][]interface{}, 0, batchSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		rows = rows[:0]
+		for len(rows) < batchSize {
+			row, ok := next()
+			if !ok {
+				break
+			}
+			rows = append(rows, row)
+		}
+		if len(rows) == 0 {
+			return total, nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return total, fmt.Errorf("%d pulled rows not inserted: %w", len(rows), err)
+		}
+
+		batch := *b
+		batch.values = rows
+		res, err := ExecWithContext(ctx, runner, &batch)
+		if err != nil {
+			return total, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+}
+
+// Prefix adds an expression to the beginning of the query.
+// Sqlizer args are embedded in place of their placeholders.
 func (b *InsertBuilder) Prefix(sql string, args ...interface{}) *InsertBuilder {
 	b.prefixes = append(b.prefixes, Expr(sql, args...))
 	return b
@@ -228,12 +489,20 @@ func (b *InsertBuilder) Into(into string) *InsertBuilder {
 
 // Columns adds insert columns to the query.
 func (b *InsertBuilder) Columns(columns ...string) *InsertBuilder {
+	if b.setMap {
+		b.err = errSetMapCombined
+		return b
+	}
 	b.columns = append(b.columns, columns...)
 	return b
 }
 
 // Values adds a single row's values to the query.
 func (b *InsertBuilder) Values(values ...interface{}) *InsertBuilder {
+	if b.setMap {
+		b.err = errSetMapCombined
+		return b
+	}
 	b.values = append(b.values, values)
 	return b
 }
@@ -254,22 +523,36 @@ func (b *InsertBuilder) ReturningSelect(from *SelectBuilder, alias string) *Inse
 	return b
 }
 
-// Suffix adds an expression to the end of the query
+// Suffix adds an expression to the end of the query.
+// Sqlizer args are embedded in place of their placeholders.
 func (b *InsertBuilder) Suffix(sql string, args ...interface{}) *InsertBuilder {
 	b.suffixes = append(b.suffixes, Expr(sql, args...))
 	return b
 }
 
-// SetMap set columns and values for insert builder from a map of column name and value
-// note that it will reset all previous columns and values was set if any
+// errSetMapCombined is returned by ToSql if columns or values of SetMap are mixed
+// with other ones.
+var errSetMapCombined = errors.New("insert SetMap cannot be combined with columns or values set otherwise")
+
+// SetMap sets columns and a single row's values of the query from a map of
+// column name and value, like UpdateBuilder.SetMap does for SET clauses.
+// Columns are sorted by name, so the query is deterministic:
+//     Insert("t").SetMap(map[string]interface{}{"b": 2, "a": 1})
+//     // INSERT INTO t (a,b) VALUES (?,?)
+//
+// SetMap cannot be combined with Columns, Values, SetStructs or another
+// SetMap in any order, ToSql returns an error then.
 func (b *InsertBuilder) SetMap(clauses map[string]interface{}) *InsertBuilder {
-	// TODO: replace resetting previous values with extending existing ones?
-	cols := make([]string, 0, len(clauses))
-	vals := make([]interface{}, 0, len(clauses))
+	if b.setMap || len(b.columns) > 0 || len(b.values) > 0 {
+		b.err = errSetMapCombined
+		return b
+	}
+	b.setMap = true
 
-	for col, val := range clauses {
-		cols = append(cols, col)
-		vals = append(vals, val)
+	cols := sortedKeys(clauses)
+	vals := make([]interface{}, len(cols))
+	for i, col := range cols {
+		vals[i] = clauses[col]
 	}
 
 	b.columns = cols
@@ -277,9 +560,87 @@ func (b *InsertBuilder) SetMap(clauses map[string]interface{}) *InsertBuilder {
 	return b
 }
 
-// Select set Select clause for insert query
-// If Values and Select are used, then Select has higher priority
+// SetStructs sets columns and adds a row's values for each element of rows,
+// which must be a slice of structs or pointers to structs.
+//
+// Columns are taken from "db" tags of the first element's fields, falling back
+// to field names. Fields tagged with `db:"-"` are skipped and fields of embedded
+// structs are included. All elements must have the same columns, otherwise
+// ToSql returns an error, as it does if columns were already set to different ones.
+// Ex:
+//     type User struct {
+//         ID   int    `db:"-"`
+//         Name string `db:"name"`
+//         Age  int    `db:"age"`
+//     }
+//     Insert("users").SetStructs([]User{{Name: "moe", Age: 13}, {Name: "larry", Age: 17}})
+func (b *InsertBuilder) SetStructs(rows interface{}) *InsertBuilder {
+	if b.setMap {
+		b.err = errSetMapCombined
+		return b
+	}
+	columns, values, err := structsToValues(rows)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	if len(values) == 0 {
+		return b
+	}
+
+	if len(b.columns) == 0 {
+		b.columns = columns
+	} else if strings.Join(b.columns, ",") != strings.Join(columns, ",") {
+		b.err = fmt.Errorf("struct columns %v do not match insert columns %v", columns, b.columns)
+		return b
+	}
+	b.values = append(b.values, values...)
+	return b
+}
+
+// Select set Select clause for insert query: "INSERT INTO t (a,b) SELECT ...".
+// Its args follow the args of WITH clause and prefixes. Values and Select cannot
+// be used together, ToSql returns an error then.
 func (b *InsertBuilder) Select(sb *SelectBuilder) *InsertBuilder {
 	b.iselect = sb
 	return b
 }
+
+// OnConflict sets conflict target columns for "ON CONFLICT" clause of the query.
+//
+// The clause is rendered as "ON CONFLICT (columns) ..." for PostgreSQL and SQLite
+// dialects and as "ON DUPLICATE KEY UPDATE ..." for MySQL dialect, where
+// conflict target columns are ignored. If Dialect is not set, Dollar
+// placeholder format means PostgreSQL and any other format means MySQL.
+func (b *InsertBuilder) OnConflict(columns ...string) *InsertBuilder {
+	b.conflict = &onConflict{columns: columns}
+	return b
+}
+
+// DoNothing sets "ON CONFLICT ... DO NOTHING" action for the query.
+//
+// MySQL does not support it, use Options("IGNORE") there.
+func (b *InsertBuilder) DoNothing() *InsertBuilder {
+	if b.conflict == nil {
+		b.conflict = &onConflict{}
+	}
+	b.conflict.doNothing = true
+	b.conflict.updates = nil
+	return b
+}
+
+// DoUpdate adds SET clauses for "ON CONFLICT ... DO UPDATE" (PostgreSQL) or
+// "ON DUPLICATE KEY UPDATE" (MySQL) action of the query. PostgreSQL and SQLite
+// require conflict target columns for it, ToSql returns an error if OnConflict
+// was not given any.
+//
+// Values could be Sqlizers, e.g. Expr("EXCLUDED.col") or Expr("VALUES(col)").
+// Columns are sorted by name.
+func (b *InsertBuilder) DoUpdate(clauses map[string]interface{}) *InsertBuilder {
+	if b.conflict == nil {
+		b.conflict = &onConflict{}
+	}
+	b.conflict.doNothing = false
+	b.conflict.updates = append(b.conflict.updates, sortedSetClauses(clauses)...)
+	return b
+}
@@ -2,6 +2,10 @@ package sqrl
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -131,6 +135,34 @@ func TestInsertBuilderSetMap(t *testing.T) {
 	assert.Equal(t, expectedArgs, args)
 }
 
+func TestInsertBuilderSetMapSorted(t *testing.T) {
+	sql, args, err := Insert("t").SetMap(map[string]interface{}{"c": 3, "a": 1, "b": Expr("NOW()")}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (a,b,c) VALUES (?,NOW(),?)", sql)
+	assert.Equal(t, []interface{}{1, 3}, args)
+}
+
+func TestInsertBuilderSetMapConflict(t *testing.T) {
+	_, _, err := Insert("t").Columns("a").SetMap(Eq{"b": 2}).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Insert("t").Values(1).SetMap(Eq{"b": 2}).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Insert("t").SetMap(Eq{"a": 1}).SetMap(Eq{"b": 2}).ToSql()
+	assert.Error(t, err)
+
+	m := map[string]interface{}{"a": 1, "b": 2}
+	_, _, err = Insert("t").SetMap(m).Columns("c").ToSql()
+	assert.EqualError(t, err, "insert SetMap cannot be combined with columns or values set otherwise")
+
+	_, _, err = Insert("t").SetMap(m).Values(3, 4).ToSql()
+	assert.EqualError(t, err, "insert SetMap cannot be combined with columns or values set otherwise")
+
+	_, _, err = Insert("t").SetMap(m).SetStructs([]struct{ A, B int }{{3, 4}}).ToSql()
+	assert.Error(t, err)
+}
+
 func TestInsertBuilderSelect(t *testing.T) {
 	sb := Select("field1").From("table1").Where(Eq{"field1": 1})
 	ib := Insert("table2").Columns("field1").Select(sb)
@@ -144,3 +176,309 @@ func TestInsertBuilderSelect(t *testing.T) {
 	expectedArgs := []interface{}{1}
 	assert.Equal(t, expectedArgs, args)
 }
+
+func TestInsertBuilderOnConflict(t *testing.T) {
+	b := Insert("a").
+		Columns("id", "foo", "counter").
+		Values(1, "bar", 1).
+		OnConflict("id").
+		DoUpdate(map[string]interface{}{
+			"foo":     Expr("EXCLUDED.foo"),
+			"counter": Expr("a.counter + ?", 1),
+		}).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "INSERT INTO a (id,foo,counter) VALUES ($1,$2,$3) " +
+		"ON CONFLICT (id) DO UPDATE SET counter = a.counter + $4, foo = EXCLUDED.foo"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, "bar", 1, 1}, args)
+}
+
+func TestInsertBuilderOnConflictDoNothing(t *testing.T) {
+	b := Insert("a").
+		Columns("id").
+		Values(1).
+		OnConflict().
+		DoNothing().
+		Returning("id").
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO a (id) VALUES ($1) ON CONFLICT DO NOTHING RETURNING id", sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestInsertBuilderOnConflictDoUpdateNoTarget(t *testing.T) {
+	_, _, err := Insert("a").Columns("id").Values(1).
+		OnConflict().
+		DoUpdate(map[string]interface{}{"id": 2}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.EqualError(t, err, "ON CONFLICT DO UPDATE requires conflict target columns, set them with OnConflict")
+
+	_, _, err = Insert("a").Columns("id").Values(1).
+		DoUpdate(map[string]interface{}{"id": 2}).
+		Dialect(SQLiteDialect).
+		ToSql()
+	assert.Error(t, err)
+
+	sql, _, err := Insert("a").Columns("id").Values(1).
+		OnConflict().
+		DoUpdate(map[string]interface{}{"id": 2}).
+		Dialect(MySQLDialect).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO a (id) VALUES (?) ON DUPLICATE KEY UPDATE id = ?", sql)
+}
+
+func TestInsertBuilderOnDuplicateKey(t *testing.T) {
+	b := Insert("a").
+		Columns("id", "foo").
+		Values(1, "bar").
+		OnConflict("id").
+		DoUpdate(map[string]interface{}{"foo": "baz"})
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO a (id,foo) VALUES (?,?) ON DUPLICATE KEY UPDATE foo = ?", sql)
+	assert.Equal(t, []interface{}{1, "bar", "baz"}, args)
+
+	_, _, err = Insert("a").Values(1).DoNothing().ToSql()
+	assert.Error(t, err)
+}
+
+func TestInsertBuilderReturningQueryRow(t *testing.T) {
+	db := &DBStub{}
+	b := Insert("a").
+		Columns("foo", "bar").
+		Values(1, 2).
+		Returning("id", "created_at").
+		PlaceholderFormat(Dollar).
+		RunWith(db)
+
+	var id int
+	err := b.QueryRow().Scan(&id)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO a (foo,bar) VALUES ($1,$2) RETURNING id, created_at", db.LastQueryRowSql)
+	assert.Equal(t, []interface{}{1, 2}, db.LastQueryRowArgs)
+}
+
+type insertTimestamps struct {
+	CreatedAt string `db:"created_at"`
+}
+
+type insertUser struct {
+	ID   int    `db:"-"`
+	Name string `db:"name"`
+	Age  int
+	insertTimestamps
+	secret string
+}
+
+func TestInsertBuilderSetStructs(t *testing.T) {
+	users := []insertUser{
+		{ID: 1, Name: "moe", Age: 13, insertTimestamps: insertTimestamps{"mon"}},
+		{ID: 2, Name: "larry", Age: 17, insertTimestamps: insertTimestamps{"tue"}, secret: "x"},
+	}
+
+	sql, args, err := Insert("users").SetStructs(users).PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name,Age,created_at) VALUES ($1,$2,$3),($4,$5,$6)", sql)
+	assert.Equal(t, []interface{}{"moe", 13, "mon", "larry", 17, "tue"}, args)
+
+	sql, args, err = Insert("users").
+		SetStructs([]*insertUser{&users[0]}).
+		SetStructs([]interface{}{users[1]}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name,Age,created_at) VALUES (?,?,?),(?,?,?)", sql)
+	assert.Equal(t, []interface{}{"moe", 13, "mon", "larry", 17, "tue"}, args)
+}
+
+func TestInsertBuilderSetStructsErr(t *testing.T) {
+	type other struct {
+		Name string `db:"name"`
+	}
+
+	_, _, err := Insert("users").SetStructs([]interface{}{insertUser{}, other{}}).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Insert("users").Columns("name").SetStructs([]insertUser{{}}).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Insert("users").SetStructs(insertUser{}).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Insert("users").SetStructs([]*insertUser{nil}).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Insert("users").SetStructs([]int{1}).ToSql()
+	assert.Error(t, err)
+}
+
+func TestInsertBuilderToSqlBatches(t *testing.T) {
+	b := Insert("a").
+		Columns("x", "y").
+		Values(1, 2).
+		Values(3, Expr("? + ?", 4, 5)).
+		Values(6, 7).
+		Values(8, 9).
+		Suffix("RETURNING ?", 0).
+		PlaceholderFormat(Dollar)
+
+	sqls, args, err := b.ToSqlBatches(6)
+	assert.NoError(t, err)
+
+	expectedSqls := []string{
+		"INSERT INTO a (x,y) VALUES ($1,$2),($3,$4 + $5) RETURNING $6",
+		"INSERT INTO a (x,y) VALUES ($1,$2),($3,$4) RETURNING $5",
+	}
+	assert.Equal(t, expectedSqls, sqls)
+
+	expectedArgs := [][]interface{}{
+		{1, 2, 3, 4, 5, 0},
+		{6, 7, 8, 9, 0},
+	}
+	assert.Equal(t, expectedArgs, args)
+
+	sqls, args, err = b.ToSqlBatches(65535)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"INSERT INTO a (x,y) VALUES ($1,$2),($3,$4 + $5),($6,$7),($8,$9) RETURNING $10"}, sqls)
+	assert.Equal(t, [][]interface{}{{1, 2, 3, 4, 5, 6, 7, 8, 9, 0}}, args)
+}
+
+// rowsExecer counts Execs and reports each bound arg as an affected row
+type rowsExecer struct {
+	queries []string
+	args    int
+}
+
+func (e *rowsExecer) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	e.queries = append(e.queries, query)
+	e.args += len(args)
+	return driver.RowsAffected(len(args)), nil
+}
+
+func TestInsertBuilderExecStream(t *testing.T) {
+	i := 0
+	gen := func() ([]interface{}, bool) {
+		if i == 1000 {
+			return nil, false
+		}
+		i++
+		return []interface{}{i}, true
+	}
+
+	db := &rowsExecer{}
+	n, err := Insert("a").Columns("x").Values(0).AddFunc(gen).ExecStream(context.Background(), db, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1001), n)
+	assert.Len(t, db.queries, 11)
+	assert.Equal(t, "INSERT INTO a (x) VALUES "+strings.TrimSuffix(strings.Repeat("(?),", 100), ","), db.queries[0])
+	assert.Equal(t, "INSERT INTO a (x) VALUES (?)", db.queries[10])
+}
+
+func TestInsertBuilderExecStreamErr(t *testing.T) {
+	db := &rowsExecer{}
+
+	n, err := Insert("a").Columns("x").ExecStream(context.Background(), db, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	_, err = Insert("a").Columns("x").Values(1).ExecStream(context.Background(), db, 0)
+	assert.Error(t, err)
+
+	_, err = Insert("a").Columns("x").Select(Select("1")).ExecStream(context.Background(), db, 10)
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	i := 0
+	gen := func() ([]interface{}, bool) {
+		i++
+		if i == 15 {
+			cancel()
+		}
+		return []interface{}{i}, i <= 30
+	}
+	n, err = Insert("a").Columns("x").AddFunc(gen).ExecStream(ctx, db, 10)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.EqualError(t, err, "10 pulled rows not inserted: context canceled")
+	assert.Equal(t, int64(10), n)
+	assert.Equal(t, 10, db.args)
+}
+
+// cancelExecer cancels the context after the first Exec
+type cancelExecer struct {
+	rowsExecer
+	cancel context.CancelFunc
+}
+
+func (e *cancelExecer) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	defer e.cancel()
+	return e.rowsExecer.ExecContext(ctx, query, args...)
+}
+
+func TestInsertBuilderExecStreamCancelBetweenBatches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	db := &cancelExecer{cancel: cancel}
+	pulled := 0
+	gen := func() ([]interface{}, bool) {
+		pulled++
+		return []interface{}{pulled}, pulled <= 30
+	}
+
+	n, err := Insert("a").Columns("x").AddFunc(gen).ExecStream(ctx, db, 10)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, int64(10), n)
+	assert.Equal(t, 10, pulled)
+}
+
+func TestInsertBuilderToSqlBatchesErr(t *testing.T) {
+	b := Insert("a").Columns("x", "y").Values(1, 2).Values(3, 4)
+
+	_, _, err := b.ToSqlBatches(0)
+	assert.Error(t, err)
+
+	_, _, err = b.ToSqlBatches(1)
+	assert.Error(t, err)
+
+	_, _, err = Insert("a").ToSqlBatches(10)
+	assert.Error(t, err)
+
+	_, _, err = Insert("a").Select(Select("x").From("b")).ToSqlBatches(10)
+	assert.Error(t, err)
+}
+
+func TestInsertBuilderValuesSqlizerArgs(t *testing.T) {
+	sql, args, err := Insert("t").Columns("a", "b", "c").
+		Values(Expr("? + ?", 1, Expr("ABS(?)", 2)), Select("MAX(b)").From("t").Where("c = ?", 3), 4).
+		ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (a,b,c) VALUES (? + ABS(?),(SELECT MAX(b) FROM t WHERE c = ?),?)", sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 4}, args)
+}
+
+func TestInsertBuilderSelectPlaceholders(t *testing.T) {
+	sb := Select("x", "y").From("src").Where("x > ? AND y < ?", 1, 2)
+	sql, args, err := Insert("t").
+		Prefix("/* etl ? */", "job").
+		Columns("a", "b").
+		Select(sb).
+		Suffix("RETURNING a + ?", 3).
+		PlaceholderFormat(Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/* etl $1 */ INSERT INTO t (a,b) SELECT x, y FROM src WHERE x > $2 AND y < $3 RETURNING a + $4", sql)
+	assert.Equal(t, []interface{}{"job", 1, 2, 3}, args)
+}
+
+func TestInsertBuilderValuesAndSelect(t *testing.T) {
+	_, _, err := Insert("t").Columns("a").Values(1).Select(Select("x").From("src")).ToSql()
+	assert.Error(t, err)
+}
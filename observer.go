@@ -0,0 +1,109 @@
+package sqrl
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Observer is notified around each database call made with a Runner returned
+// by WithObserver, e.g. to trace or measure queries.
+//
+// Before is called before the call with the rendered query and args, After is
+// called when the call returns, with its error and duration. For QueryRow the
+// call ends when the row is scanned, so After is called by Scan with its error.
+// Calls without a context are reported with context.Background.
+type Observer interface {
+	Before(ctx context.Context, query string, args []interface{})
+	After(ctx context.Context, query string, err error, duration time.Duration)
+}
+
+// WithObserver returns a Runner calling observer around each Exec, Query and
+// QueryRow of runner, as well as their Context variants:
+//
+//	Select("*").From("users").RunWith(WithObserver(db, tracer)).Query()
+//
+// runner is wrapped like RunWith does, so it could be database/sql.DB or Tx.
+// QueryRow fails with ErrRunnerNotQueryRunner or ErrRunnerNotQueryRunnerContext
+// if runner does not implement it.
+func WithObserver(runner BaseRunner, observer Observer) Runner {
+	return &observedRunner{runner: wrapRunner(runner), observer: observer}
+}
+
+type observedRunner struct {
+	runner   BaseRunner
+	observer Observer
+}
+
+func (r *observedRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	ctx := context.Background()
+	start := r.before(ctx, query, args)
+	res, err := r.runner.Exec(query, args...)
+	r.observer.After(ctx, query, err, time.Since(start))
+	return res, err
+}
+
+func (r *observedRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	start := r.before(ctx, query, args)
+	res, err := r.runner.ExecContext(ctx, query, args...)
+	r.observer.After(ctx, query, err, time.Since(start))
+	return res, err
+}
+
+func (r *observedRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	ctx := context.Background()
+	start := r.before(ctx, query, args)
+	rows, err := r.runner.Query(query, args...)
+	r.observer.After(ctx, query, err, time.Since(start))
+	return rows, err
+}
+
+func (r *observedRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	start := r.before(ctx, query, args)
+	rows, err := r.runner.QueryContext(ctx, query, args...)
+	r.observer.After(ctx, query, err, time.Since(start))
+	return rows, err
+}
+
+func (r *observedRunner) QueryRow(query string, args ...interface{}) RowScanner {
+	ctx := context.Background()
+	start := r.before(ctx, query, args)
+	queryRower, ok := r.runner.(QueryRower)
+	if !ok {
+		r.observer.After(ctx, query, ErrRunnerNotQueryRunner, time.Since(start))
+		return &Row{err: ErrRunnerNotQueryRunner}
+	}
+	row := queryRower.QueryRow(query, args...)
+	return &observedRow{RowScanner: row, observer: r.observer, ctx: ctx, query: query, start: start}
+}
+
+func (r *observedRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	start := r.before(ctx, query, args)
+	queryRower, ok := r.runner.(QueryRowerContext)
+	if !ok {
+		r.observer.After(ctx, query, ErrRunnerNotQueryRunnerContext, time.Since(start))
+		return &Row{err: ErrRunnerNotQueryRunnerContext}
+	}
+	row := queryRower.QueryRowContext(ctx, query, args...)
+	return &observedRow{RowScanner: row, observer: r.observer, ctx: ctx, query: query, start: start}
+}
+
+func (r *observedRunner) before(ctx context.Context, query string, args []interface{}) time.Time {
+	r.observer.Before(ctx, query, args)
+	return time.Now()
+}
+
+// observedRow reports the end of QueryRow call to the observer on Scan
+type observedRow struct {
+	RowScanner
+	observer Observer
+	ctx      context.Context
+	query    string
+	start    time.Time
+}
+
+func (r *observedRow) Scan(dest ...interface{}) error {
+	err := r.RowScanner.Scan(dest...)
+	r.observer.After(r.ctx, r.query, err, time.Since(r.start))
+	return err
+}
@@ -0,0 +1,109 @@
+package sqrl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingObserver records calls of Observer methods
+type recordingObserver struct {
+	calls []string
+	ctxs  []context.Context
+	args  [][]interface{}
+	errs  []error
+}
+
+func (o *recordingObserver) Before(ctx context.Context, query string, args []interface{}) {
+	o.calls = append(o.calls, "before "+query)
+	o.ctxs = append(o.ctxs, ctx)
+	o.args = append(o.args, args)
+}
+
+func (o *recordingObserver) After(ctx context.Context, query string, err error, duration time.Duration) {
+	o.calls = append(o.calls, "after "+query)
+	o.errs = append(o.errs, err)
+}
+
+func TestWithObserver(t *testing.T) {
+	db := &DBStub{}
+	o := &recordingObserver{}
+	b := Select("*").From("t").Where("a = ?", 1).RunWith(WithObserver(db, o))
+
+	expectedSql := "SELECT * FROM t WHERE a = ?"
+	observed := []string{"before " + expectedSql, "after " + expectedSql}
+
+	b.Exec()
+	b.Query()
+	assert.Equal(t, append(observed, observed...), o.calls)
+	assert.Equal(t, []interface{}{1}, o.args[0])
+	assert.Equal(t, context.Background(), o.ctxs[0])
+	assert.Equal(t, expectedSql, db.LastExecSql)
+	assert.Equal(t, expectedSql, db.LastQuerySql)
+
+	o.calls = nil
+	row := b.QueryRow()
+	assert.Equal(t, observed[:1], o.calls)
+	assert.NoError(t, row.Scan())
+	assert.Equal(t, observed, o.calls)
+
+	o.calls, o.ctxs = nil, nil
+	ctx := context.WithValue(context.Background(), ctxKey{}, "v")
+	b.ExecContext(ctx)
+	b.QueryContext(ctx)
+	b.QueryRowContext(ctx).Scan()
+	assert.Equal(t, append(observed, append(observed, observed...)...), o.calls)
+	assert.Equal(t, []context.Context{ctx, ctx, ctx}, o.ctxs)
+	assert.Equal(t, ctx, db.LastCtx)
+}
+
+func TestWithObserverErrors(t *testing.T) {
+	failure := errors.New("failure")
+	db := &DBStub{err: failure}
+	o := &recordingObserver{}
+
+	_, err := Update("t").Set("a", 1).RunWith(WithObserver(db, o)).Exec()
+	assert.Equal(t, failure, err)
+	assert.Equal(t, []error{failure}, o.errs)
+
+	o.errs = nil
+	r := WithObserver(&noQueryRowRunner{}, o)
+	assert.Equal(t, ErrRunnerNotQueryRunner, r.QueryRow("SELECT 1").Scan())
+	assert.Equal(t, ErrRunnerNotQueryRunnerContext, r.QueryRowContext(context.Background(), "SELECT 1").Scan())
+	assert.Equal(t, []error{ErrRunnerNotQueryRunner, ErrRunnerNotQueryRunnerContext}, o.errs)
+}
+
+func TestWithObserverDB(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+	d.setRows("SELECT name FROM users WHERE id = ?", []string{"name"})
+
+	o := &recordingObserver{}
+	var name string
+	err := Select("name").From("users").Where("id = ?", 1).RunWith(WithObserver(db, o)).Scan(&name)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Equal(t, []error{sql.ErrNoRows}, o.errs)
+}
+
+// noQueryRowRunner is a BaseRunner without QueryRow methods
+type noQueryRowRunner struct{}
+
+func (noQueryRowRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+func (noQueryRowRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+func (noQueryRowRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, nil
+}
+
+func (noQueryRowRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, nil
+}
@@ -15,35 +15,104 @@ func newPart(pred interface{}, args ...interface{}) Sqlizer {
 }
 
 func (p part) ToSql() (sql string, args []interface{}, err error) {
+	return p.toSqlDialect(NoDialect)
+}
+
+func (p part) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
 	switch pred := p.pred.(type) {
 	case nil:
 		// no-op
 	case Sqlizer:
-		sql, args, err = pred.ToSql()
+		sql, args, err = dialectToSql(pred, d)
 	case string:
-		sql = pred
-		args = p.args
+		sql, args, err = Expr(pred, p.args...).toSqlDialect(d)
 	default:
 		err = fmt.Errorf("expected string or Sqlizer, not %T", pred)
 	}
 	return
 }
 
-func appendToSql(parts []Sqlizer, w io.Writer, sep string, args []interface{}) ([]interface{}, error) {
-	for i, p := range parts {
-		partSql, partArgs, err := p.ToSql()
+// rawSqlizer is implemented by statement builders. toSqlRaw builds SQL leaving
+// placeholders as is, so it can be embedded into another statement.
+type rawSqlizer interface {
+	toSqlRaw() (string, []interface{}, error)
+}
+
+// NestedToSql builds SQL of s to be embedded into another statement, leaving
+// "?" placeholders as is regardless of PlaceholderFormat of s.
+//
+// Statement builders replace placeholders only when built at the top level,
+// so a builder with any PlaceholderFormat could be embedded into a statement
+// with another one and placeholders are numbered consistently by the outermost
+// statement. Custom Sqlizers embedding other Sqlizers should build them with
+// NestedToSql rather than ToSql to keep this rule.
+func NestedToSql(s Sqlizer) (string, []interface{}, error) {
+	return nestedToSql(s)
+}
+
+// nestedToSql builds SQL of s to be embedded into another statement.
+// Placeholders are replaced only once, by the outermost statement, so all parts
+// of the statement are numbered consistently.
+func nestedToSql(s Sqlizer) (string, []interface{}, error) {
+	if raw, ok := s.(rawSqlizer); ok {
+		return raw.toSqlRaw()
+	}
+	return s.ToSql()
+}
+
+// valueToSql builds SQL of a single value, e.g. of a VALUES row or SET clause,
+// for a statement of dialect d. Sqlizers are embedded with their args, statement
+// builders as parenthesized subqueries; other values are bound as args.
+func valueToSql(val interface{}, d Dialect) (string, []interface{}, error) {
+	s, ok := val.(Sqlizer)
+	if !ok {
+		return "?", []interface{}{val}, nil
+	}
+
+	sql, args, err := dialectToSql(s, d)
+	if err != nil {
+		return "", nil, err
+	}
+	if isStatement(s) {
+		sql = fmt.Sprintf("(%s)", sql)
+	}
+	return sql, args, nil
+}
+
+// dialectSqlizer is implemented by Sqlizers rendered differently depending on
+// Dialect of the statement they are embedded into, e.g. ILike.
+type dialectSqlizer interface {
+	toSqlDialect(d Dialect) (string, []interface{}, error)
+}
+
+// dialectToSql builds SQL of s to be embedded into a statement of dialect d.
+func dialectToSql(s Sqlizer, d Dialect) (string, []interface{}, error) {
+	if ds, ok := s.(dialectSqlizer); ok {
+		return ds.toSqlDialect(d)
+	}
+	return nestedToSql(s)
+}
+
+// appendToSql writes SQL of parts joined with sep to w and appends their args
+// to args, so SQL and args of each part stay in the same order. Parts with empty
+// SQL are skipped.
+func appendToSql(parts []Sqlizer, w io.Writer, sep string, d Dialect, args []interface{}) ([]interface{}, error) {
+	written := false
+	for _, p := range parts {
+		partSql, partArgs, err := dialectToSql(p, d)
 		if err != nil {
 			return nil, err
 		} else if len(partSql) == 0 {
 			continue
 		}
 
-		if i > 0 {
+		if written {
 			_, err := io.WriteString(w, sep)
 			if err != nil {
 				return nil, err
 			}
 		}
+		written = true
 
 		_, err = io.WriteString(w, partSql)
 		if err != nil {
@@ -19,6 +19,63 @@ func Array(arr interface{}) sqrl.Sqlizer {
 	return array{arr}
 }
 
+// Any builds "column = ANY(?)" expression with arr bound as a single arg.
+//
+// Unlike sqrl.Eq with a slice it produces the same SQL for any number of
+// elements, arr could be wrapped with Array (or pq.Array) to be accepted by the driver.
+// Sqlizers are embedded: Any("id", Array(ids)) or Any("id", sqrl.Select("id").From("t")).
+func Any(column string, arr interface{}) sqrl.Sqlizer {
+	return sqrl.Expr(column+" = ANY(?)", arr)
+}
+
+// All builds "column = ALL(?)" expression with arr bound as a single arg.
+//
+// See Any.
+func All(column string, arr interface{}) sqrl.Sqlizer {
+	return sqrl.Expr(column+" = ALL(?)", arr)
+}
+
+// Contains builds "column @> ?" expression checking that array column contains
+// all elements of arr, which is bound as a single arg or embedded if it is a
+// Sqlizer: Contains("tags", ArrayExpr("a", "b")).
+func Contains(column string, arr interface{}) sqrl.Sqlizer {
+	return sqrl.Expr(column+" @> ?", arr)
+}
+
+// ArrayExpr builds Postgres array constructor "ARRAY[?, ?]" with each value
+// bound as an arg, or embedded if it is a Sqlizer.
+//
+// Unlike Array, which binds the whole array as a single literal, elements
+// could be of any type and expressions. Postgres cannot infer type of an
+// empty array, so ToSql returns an error for no values, use ArrayOf instead.
+func ArrayExpr(values ...interface{}) sqrl.Sqlizer {
+	return arrayExpr{values: values}
+}
+
+// ArrayOf is like ArrayExpr, but the array is cast to array of elemType:
+// "ARRAY[?, ?]::elemType[]", so it could be empty: "ARRAY[]::elemType[]".
+func ArrayOf(elemType string, values ...interface{}) sqrl.Sqlizer {
+	return arrayExpr{elemType: elemType, values: values}
+}
+
+type arrayExpr struct {
+	elemType string
+	values   []interface{}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (a arrayExpr) ToSql() (string, []interface{}, error) {
+	if len(a.values) == 0 && a.elemType == "" {
+		return "", nil, fmt.Errorf("empty array requires element type, use ArrayOf")
+	}
+
+	sql := "ARRAY[" + strings.TrimPrefix(strings.Repeat(", ?", len(a.values)), ", ") + "]"
+	if a.elemType != "" {
+		sql = fmt.Sprintf("%s::%s[]", sql, a.elemType)
+	}
+	return sqrl.Expr(sql, a.values...).ToSql()
+}
+
 // ToSql builds the query into a SQL string and bound args.
 func (a array) ToSql() (string, []interface{}, error) {
 	if err := checkArrayType(a.value); err != nil {
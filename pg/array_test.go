@@ -72,3 +72,76 @@ func ExampleArray() {
 	// INSERT INTO posts (content,tags) VALUES ($1,$2)
 	// [Lorem Ipsum {"foo","bar"}]
 }
+
+func TestAnyAll(t *testing.T) {
+	sql, args, err := pg.Any("id", []int{1, 2, 3}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "id = ANY(?)", sql)
+	assert.Equal(t, []interface{}{[]int{1, 2, 3}}, args)
+
+	sql, args, err = pg.All("tag", pg.Array([]string{"a", "b"})).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "tag = ALL(?)", sql)
+	assert.Equal(t, []interface{}{`{"a","b"}`}, args)
+
+	_, _, err = pg.Any("id", pg.Array(42)).ToSql()
+	assert.Error(t, err)
+}
+
+func TestAnyInQuery(t *testing.T) {
+	sql, args, err := sqrl.Select("*").
+		From("users").
+		Where(sqrl.Or{
+			pg.Any("id", pg.Array([]int{1, 2})),
+			sqrl.And{pg.All("role", pg.Array([]string{"admin"})), sqrl.Eq{"active": true}},
+		}).
+		Where(pg.Any("group_id", sqrl.Select("id").From("groups").Where("owner = ?", 3))).
+		PlaceholderFormat(sqrl.Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE (id = ANY($1) OR (role = ALL($2) AND active = $3)) "+
+		"AND group_id = ANY(SELECT id FROM groups WHERE owner = $4)", sql)
+	assert.Equal(t, []interface{}{"{1,2}", `{"admin"}`, true, 3}, args)
+}
+
+func TestArrayExpr(t *testing.T) {
+	valid := []struct {
+		op   sqrl.Sqlizer
+		sql  string
+		args []interface{}
+	}{
+		{pg.ArrayExpr(1), "ARRAY[?]", []interface{}{1}},
+		{pg.ArrayExpr(1, "a", 2.5), "ARRAY[?, ?, ?]", []interface{}{1, "a", 2.5}},
+		{pg.ArrayExpr(sqrl.Expr("now()"), sqrl.Expr("? + 1", 2)), "ARRAY[now(), ? + 1]", []interface{}{2}},
+		{pg.ArrayOf("int"), "ARRAY[]::int[]", nil},
+		{pg.ArrayOf("text", "a", "b"), "ARRAY[?, ?]::text[]", []interface{}{"a", "b"}},
+	}
+
+	for _, test := range valid {
+		sql, args, err := test.op.ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, test.sql, sql)
+		assert.Equal(t, test.args, args)
+	}
+
+	_, _, err := pg.ArrayExpr().ToSql()
+	assert.Error(t, err)
+}
+
+func TestArrayExprInQuery(t *testing.T) {
+	sql, args, err := sqrl.Select("id").
+		Column(sqrl.Alias(pg.ArrayExpr("a", sqrl.Expr("name")), "names")).
+		From("users").
+		Where(pg.Any("id", pg.ArrayExpr(1, 2))).
+		Where(pg.Contains("tags", pg.ArrayOf("text", "x"))).
+		Where(pg.Contains("roles", pg.ArrayOf("text"))).
+		PlaceholderFormat(sqrl.Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	expectedSql := "SELECT id, (ARRAY[$1, name]) AS names FROM users " +
+		"WHERE id = ANY(ARRAY[$2, $3]) AND tags @> ARRAY[$4]::text[] AND roles @> ARRAY[]::text[]"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"a", 1, 2, "x"}, args)
+}
@@ -3,6 +3,8 @@ package pg
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/elgris/sqrl"
 )
@@ -37,3 +39,80 @@ func (jo jsonOp) ToSql() (string, []interface{}, error) {
 
 	return fmt.Sprintf("?::%s", jo.tpe), []interface{}{string(v)}, nil
 }
+
+// JSONGet builds "column->key" expression getting JSON object field or array
+// element (if key is an integer).
+//
+// Column could be a column name or Sqlizer, e.g. another JSONGet:
+// JSONGetText(JSONGet("data", "a"), "b") is "data->'a'->>'b'"
+func JSONGet(column interface{}, key interface{}) sqrl.Sqlizer {
+	return jsonPathOp{column: column, op: "->", key: key}
+}
+
+// JSONGetText builds "column->>key" expression getting JSON object field or
+// array element (if key is an integer) as text.
+//
+// See JSONGet.
+func JSONGetText(column interface{}, key interface{}) sqrl.Sqlizer {
+	return jsonPathOp{column: column, op: "->>", key: key}
+}
+
+// JSONContains builds "column @> ?::jsonb" expression, value is serialized with json.Marshal.
+func JSONContains(column interface{}, value interface{}) sqrl.Sqlizer {
+	return jsonOperator{column: column, op: "@>", value: JSONB(value)}
+}
+
+// JSONHasKey builds "column ? key" expression checking whether key exists in JSON object.
+// The key is bound as an arg.
+func JSONHasKey(column interface{}, key string) sqrl.Sqlizer {
+	return jsonOperator{column: column, op: "??", value: sqrl.Expr("?", key)}
+}
+
+type jsonPathOp struct {
+	column interface{}
+	op     string
+	key    interface{}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (jp jsonPathOp) ToSql() (string, []interface{}, error) {
+	var key string
+	switch k := jp.key.(type) {
+	case string:
+		// question marks in the literal must not be treated as placeholders
+		key = "'" + strings.NewReplacer("'", "''", "?", "??").Replace(k) + "'"
+	case int:
+		key = strconv.Itoa(k)
+	default:
+		return "", nil, fmt.Errorf("Expected JSON key of type string or int, got %T", jp.key)
+	}
+
+	return jsonColumnExpr(jp.column, jp.op+key)
+}
+
+type jsonOperator struct {
+	column interface{}
+	op     string
+	value  sqrl.Sqlizer
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (jo jsonOperator) ToSql() (string, []interface{}, error) {
+	sql, args, err := jsonColumnExpr(jo.column, "")
+	if err != nil {
+		return "", nil, err
+	}
+	return sqrl.Expr(sql+" "+jo.op+" ?", append(args, jo.value)...).ToSql()
+}
+
+// jsonColumnExpr builds column expression followed by given SQL
+func jsonColumnExpr(column interface{}, sql string) (string, []interface{}, error) {
+	switch c := column.(type) {
+	case string:
+		return c + sql, nil, nil
+	case sqrl.Sqlizer:
+		return sqrl.Expr("?"+sql, c).ToSql()
+	default:
+		return "", nil, fmt.Errorf("Expected column of type string or Sqlizer, got %T", column)
+	}
+}
@@ -71,3 +71,63 @@ func ExampleJSONB() {
 	// INSERT INTO posts (content,tags) VALUES ($1,$2::jsonb)
 	// [Lorem Ipsum ["foo","bar"]]
 }
+
+func TestJSONOperators(t *testing.T) {
+	valid := []struct {
+		op   sqrl.Sqlizer
+		sql  string
+		args []interface{}
+	}{
+		{pg.JSONGet("data", "a"), "data->'a'", nil},
+		{pg.JSONGet("data", 2), "data->2", nil},
+		{pg.JSONGetText("data", "it's"), "data->>'it''s'", nil},
+		{pg.JSONGetText(pg.JSONGet("data", "a"), "b"), "data->'a'->>'b'", nil},
+		{pg.JSONGetText(pg.JSONGet(pg.JSONGet("data", "a"), 0), "b"), "data->'a'->0->>'b'", nil},
+		{pg.JSONGet("data", "what?"), "data->'what??'", nil},
+		{pg.JSONContains("data", map[string]int{"a": 1}), "data @> ?::jsonb", []interface{}{`{"a":1}`}},
+		{pg.JSONContains(pg.JSONGet("data", "a"), []int{1}), "data->'a' @> ?::jsonb", []interface{}{`[1]`}},
+		{pg.JSONHasKey("data", "a"), "data ?? ?", []interface{}{"a"}},
+	}
+
+	for _, test := range valid {
+		sql, args, err := test.op.ToSql()
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.sql, sql)
+		if test.args == nil {
+			assert.Empty(t, args)
+		} else {
+			assert.Equal(t, test.args, args)
+		}
+	}
+}
+
+func TestJSONOperatorsErr(t *testing.T) {
+	invalid := []sqrl.Sqlizer{
+		pg.JSONGet("data", 1.5),
+		pg.JSONGet(42, "a"),
+		pg.JSONContains(42, "a"),
+		pg.JSONContains("data", invalidValue{}),
+	}
+
+	for _, test := range invalid {
+		_, _, err := test.ToSql()
+		assert.Error(t, err, "Expected error at case %+v", test)
+	}
+}
+
+func TestJSONOperatorsInQuery(t *testing.T) {
+	sql, args, err := sqrl.Select("id").
+		Column(sqrl.Alias(pg.JSONGetText(pg.JSONGet("data", "a"), "b"), "b")).
+		From("docs").
+		Where(pg.JSONHasKey("data", "a")).
+		Where(pg.JSONContains("data", map[string]string{"type": "post"})).
+		Where(sqrl.Expr("? = ?", pg.JSONGetText("data", "title?"), "foo")).
+		PlaceholderFormat(sqrl.Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, (data->'a'->>'b') AS b FROM docs "+
+		"WHERE data ? $1 AND data @> $2::jsonb AND data->>'title?' = $3", sql)
+	assert.Equal(t, []interface{}{"a", `{"type":"post"}`, "foo"}, args)
+}
@@ -2,7 +2,12 @@ package sqrl
 
 import (
 	"bytes"
+	stdsql "database/sql"
+	"database/sql/driver"
 	"fmt"
+	"math"
+	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -14,20 +19,53 @@ type PlaceholderFormat interface {
 	ReplacePlaceholders(sql string) (string, error)
 }
 
+// ArgsPlaceholderFormat is a PlaceholderFormat that also needs to change args
+// bound to placeholders.
+//
+// ReplacePlaceholdersArgs takes a SQL statement with its args and returns the
+// statement with replaced placeholders and args to be passed to database/sql.
+type ArgsPlaceholderFormat interface {
+	PlaceholderFormat
+	ReplacePlaceholdersArgs(sql string, args []interface{}) (string, []interface{}, error)
+}
+
 var (
 	// Question is a PlaceholderFormat instance that leaves placeholders as
-	// question marks.
+	// question marks. Like the other formats it unescapes "??" to a literal
+	// "?", so SQL written for Dollar, e.g. the jsonb operator "data ?? 'k'",
+	// renders the same with Question. Earlier versions left "??" as is.
 	Question = questionFormat{}
 
 	// Dollar is a PlaceholderFormat instance that replaces placeholders with
 	// dollar-prefixed positional placeholders (e.g. $1, $2, $3).
 	Dollar = dollarFormat{}
+
+	// Colon is a PlaceholderFormat instance that replaces placeholders with
+	// colon-prefixed positional placeholders (e.g. :1, :2, :3).
+	Colon = colonFormat{}
+
+	// AtP is a PlaceholderFormat instance that replaces placeholders with
+	// "@p"-prefixed positional placeholders (e.g. @p1, @p2, @p3) used by SQL Server.
+	AtP = atpFormat{}
+
+	// Named is an ArgsPlaceholderFormat instance that replaces placeholders with
+	// colon-prefixed named placeholders (e.g. :arg0, :arg1) and wraps args into
+	// database/sql.NamedArg with the same names. Args that are already
+	// sql.NamedArg keep their names; a name used by several placeholders is
+	// passed once, so it must be bound to the same value each time.
+	Named = namedFormat{prefix: ":"}
 )
 
 type questionFormat struct{}
 
 func (_ questionFormat) ReplacePlaceholders(sql string) (string, error) {
-	return sql, nil
+	if !strings.Contains(sql, "??") {
+		return sql, nil
+	}
+	return replacePlaceholders(sql, func(buf *bytes.Buffer, i int) error {
+		buf.WriteByte('?')
+		return nil
+	})
 }
 
 type dollarFormat struct{}
@@ -39,6 +77,162 @@ func (_ dollarFormat) ReplacePlaceholders(sql string) (string, error) {
 	})
 }
 
+type colonFormat struct{}
+
+func (_ colonFormat) ReplacePlaceholders(sql string) (string, error) {
+	return replacePlaceholders(sql, func(buf *bytes.Buffer, i int) error {
+		fmt.Fprintf(buf, ":%d", i)
+		return nil
+	})
+}
+
+type atpFormat struct{}
+
+func (_ atpFormat) ReplacePlaceholders(sql string) (string, error) {
+	return replacePlaceholders(sql, func(buf *bytes.Buffer, i int) error {
+		fmt.Fprintf(buf, "@p%d", i)
+		return nil
+	})
+}
+
+type namedFormat struct {
+	prefix string
+}
+
+func (f namedFormat) ReplacePlaceholders(sql string) (string, error) {
+	return replacePlaceholders(sql, func(buf *bytes.Buffer, i int) error {
+		fmt.Fprintf(buf, "%sarg%d", f.prefix, i-1)
+		return nil
+	})
+}
+
+func (f namedFormat) ReplacePlaceholdersArgs(sql string, args []interface{}) (string, []interface{}, error) {
+	namedArgs := make([]interface{}, 0, len(args))
+	values := make(map[string]interface{}, len(args))
+	placeholders := 0
+	sql, err := replacePlaceholders(sql, func(buf *bytes.Buffer, i int) error {
+		if i > len(args) {
+			return fmt.Errorf("no arg for placeholder %d, got %d args", i, len(args))
+		}
+		placeholders = i
+
+		arg, ok := args[i-1].(stdsql.NamedArg)
+		if !ok {
+			arg = stdsql.Named(fmt.Sprintf("arg%d", i-1), args[i-1])
+		}
+		buf.WriteString(f.prefix)
+		buf.WriteString(arg.Name)
+
+		if value, seen := values[arg.Name]; seen {
+			if !reflect.DeepEqual(value, arg.Value) {
+				return fmt.Errorf("named arg %q is bound to different values", arg.Name)
+			}
+			return nil
+		}
+		values[arg.Name] = arg.Value
+		namedArgs = append(namedArgs, arg)
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if placeholders != len(args) {
+		return "", nil, fmt.Errorf("got %d args for %d placeholders", len(args), placeholders)
+	}
+	return sql, namedArgs, nil
+}
+
+// InlineSafe returns an ArgsPlaceholderFormat inlining args of numeric and
+// boolean kinds into SQL as literals, e.g. for constant folding of cacheable
+// queries, and replacing the other placeholders with f.
+// Ex:
+//     Select("*").From("t").Where("a = ? AND b = ?", 1, "x").PlaceholderFormat(InlineSafe(Dollar))
+//     // SELECT * FROM t WHERE a = 1 AND b = $1, args: ["x"]
+//
+// Only values of int, uint, float and bool kinds, including named types of
+// these kinds, are inlined: integers and finite floats are formatted as
+// decimal numbers, negative ones in parentheses, so "a -?" can never become a
+// "--" comment, and bools as TRUE or FALSE, which SQL Server and Oracle do not
+// support. Strings, []byte, time.Time, NaN and infinite floats, values
+// implementing driver.Valuer and all other args stay bound, so SQL is never
+// built from their text.
+func InlineSafe(f PlaceholderFormat) ArgsPlaceholderFormat {
+	return inlineSafeFormat{f}
+}
+
+type inlineSafeFormat struct {
+	f PlaceholderFormat
+}
+
+func (f inlineSafeFormat) ReplacePlaceholders(sql string) (string, error) {
+	return f.f.ReplacePlaceholders(sql)
+}
+
+func (f inlineSafeFormat) ReplacePlaceholdersArgs(sql string, args []interface{}) (string, []interface{}, error) {
+	bound := make([]interface{}, 0, len(args))
+	placeholders := 0
+	// escaped question marks are kept for f
+	sql, err := scanPlaceholders(sql, "??", func(buf *bytes.Buffer, i int) error {
+		placeholders = i
+		if i <= len(args) {
+			if literal, ok := inlineLiteral(args[i-1]); ok {
+				buf.WriteString(literal)
+				return nil
+			}
+			bound = append(bound, args[i-1])
+		}
+		buf.WriteString("?")
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	// args without placeholders are passed on, so f could report the mismatch
+	if placeholders < len(args) {
+		bound = append(bound, args[placeholders:]...)
+	}
+
+	if af, ok := f.f.(ArgsPlaceholderFormat); ok {
+		return af.ReplacePlaceholdersArgs(sql, bound)
+	}
+	sql, err = f.f.ReplacePlaceholders(sql)
+	return sql, bound, err
+}
+
+// inlineLiteral formats arg as SQL literal if it is safe to inline, see InlineSafe
+func inlineLiteral(arg interface{}) (string, bool) {
+	if _, ok := arg.(driver.Valuer); ok {
+		return "", false
+	}
+
+	var literal string
+	v := reflect.ValueOf(arg)
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return "TRUE", true
+		}
+		return "FALSE", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		literal = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		literal = strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", false
+		}
+		literal = strconv.FormatFloat(f, 'g', -1, v.Type().Bits())
+	default:
+		return "", false
+	}
+
+	if strings.HasPrefix(literal, "-") {
+		literal = "(" + literal + ")"
+	}
+	return literal, true
+}
+
 // Placeholders returns a string with count ? placeholders joined with commas.
 func Placeholders(count int) string {
 	if count < 1 {
@@ -48,25 +242,29 @@ func Placeholders(count int) string {
 	return strings.Repeat(",?", count)[1:]
 }
 
+// replacePlaceholders calls replace for each ? placeholder in sql.
+// Escaped question marks ("??") are unescaped into literal "?".
 func replacePlaceholders(sql string, replace func(buf *bytes.Buffer, i int) error) (string, error) {
+	return scanPlaceholders(sql, "?", replace)
+}
+
+// scanPlaceholders calls replace for each ? placeholder in sql and writes
+// literal for each escaped question mark ("??").
+func scanPlaceholders(sql string, literal string, replace func(buf *bytes.Buffer, i int) error) (string, error) {
 	buf := &bytes.Buffer{}
 	i := 0
 	for {
-		p := strings.Index(sql, "?")
+		p := strings.IndexByte(sql, '?')
 		if p == -1 {
 			break
 		}
 
-		if len(sql[p:]) > 1 && sql[p:p+2] == "??" { // escape ?? => ?
-			buf.WriteString(sql[:p])
-			buf.WriteString("?")
-			if len(sql[p:]) == 1 {
-				break
-			}
+		buf.WriteString(sql[:p])
+		if len(sql[p:]) > 1 && sql[p+1] == '?' { // escape ?? => ?
+			buf.WriteString(literal)
 			sql = sql[p+2:]
 		} else {
 			i++
-			buf.WriteString(sql[:p])
 			if err := replace(buf, i); err != nil {
 				return "", err
 			}
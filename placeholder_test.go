@@ -1,8 +1,11 @@
 package sqrl
 
 import (
+	"database/sql"
+	"math"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -13,6 +16,17 @@ func TestQuestion(t *testing.T) {
 	assert.Equal(t, sql, s)
 }
 
+func TestQuestionUnescape(t *testing.T) {
+	s, err := Question.ReplacePlaceholders("data ?? 'key' AND a = ? AND data ??| array[?]")
+	assert.NoError(t, err)
+	assert.Equal(t, "data ? 'key' AND a = ? AND data ?| array[?]", s)
+
+	s, args, err := Select("a").From("b").Where("c ?? 'k' AND d = ?", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM b WHERE c ? 'k' AND d = ?", s)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
 func TestDollar(t *testing.T) {
 	sql := "x = ? AND y = ?"
 	s, _ := Dollar.ReplacePlaceholders(sql)
@@ -41,3 +55,247 @@ func BenchmarkPlaceholdersArray(b *testing.B) {
 func BenchmarkPlaceholdersStrings(b *testing.B) {
 	Placeholders(b.N)
 }
+
+func TestNamed(t *testing.T) {
+	s, err := Named.ReplacePlaceholders("x = ? AND y = ?")
+	assert.NoError(t, err)
+	assert.Equal(t, "x = :arg0 AND y = :arg1", s)
+
+	s, args, err := Named.ReplacePlaceholdersArgs("x = ? AND y = ? AND z = ??", []interface{}{1, sql.Named("foo", 2)})
+	assert.NoError(t, err)
+	assert.Equal(t, "x = :arg0 AND y = :foo AND z = ?", s)
+	assert.Equal(t, []interface{}{sql.Named("arg0", 1), sql.Named("foo", 2)}, args)
+
+	_, _, err = Named.ReplacePlaceholdersArgs("x = ? AND y = ?", []interface{}{1})
+	assert.Error(t, err)
+
+	_, _, err = Named.ReplacePlaceholdersArgs("x = ?", []interface{}{1, 2})
+	assert.Error(t, err)
+}
+
+func TestNamedBuilder(t *testing.T) {
+	b := Select("a").
+		From("b").
+		Where(Eq{"c": 1}).
+		Where("d IN (?,?)", "x", "y").
+		PlaceholderFormat(Named)
+
+	s, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM b WHERE c = :arg0 AND d IN (:arg1,:arg2)", s)
+	assert.Equal(t, []interface{}{sql.Named("arg0", 1), sql.Named("arg1", "x"), sql.Named("arg2", "y")}, args)
+}
+
+func TestColon(t *testing.T) {
+	sql := "x = ? AND y = ? AND z ?? 'k'"
+	s, _ := Colon.ReplacePlaceholders(sql)
+	assert.Equal(t, "x = :1 AND y = :2 AND z ? 'k'", s)
+}
+
+func TestAtP(t *testing.T) {
+	sql := "INSERT INTO t VALUES (" + Placeholders(12) + ") -- ?? literal"
+	s, _ := AtP.ReplacePlaceholders(sql)
+	assert.Equal(t, "INSERT INTO t VALUES (@p1,@p2,@p3,@p4,@p5,@p6,@p7,@p8,@p9,@p10,@p11,@p12) -- ? literal", s)
+}
+
+func TestAtPBuilder(t *testing.T) {
+	sb := StatementBuilder.PlaceholderFormat(AtP)
+
+	s, args, err := sb.Select("a").From("b").Where("c = ? AND d ?? e", 1).Where(Eq{"f": []int{2, 3}}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM b WHERE c = @p1 AND d ? e AND f IN (@p2,@p3)", s)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+
+	s, _, err = StatementBuilder.Dialect(SQLServerDialect).Update("a").Set("b", 1).Where("c = ?", 2).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE a SET b = @p1 WHERE c = @p2", s)
+}
+
+func TestEscapeAllFormats(t *testing.T) {
+	sql := "data ?? 'key' AND a = ? AND data ??| array[?] AND b = ?"
+	expected := map[PlaceholderFormat]string{
+		Question: "data ? 'key' AND a = ? AND data ?| array[?] AND b = ?",
+		Dollar:   "data ? 'key' AND a = $1 AND data ?| array[$2] AND b = $3",
+		Colon:    "data ? 'key' AND a = :1 AND data ?| array[:2] AND b = :3",
+		AtP:      "data ? 'key' AND a = @p1 AND data ?| array[@p2] AND b = @p3",
+		Named:    "data ? 'key' AND a = :arg0 AND data ?| array[:arg1] AND b = :arg2",
+	}
+
+	for format, expectedSql := range expected {
+		s, err := format.ReplacePlaceholders(sql)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedSql, s)
+	}
+}
+
+func TestEscapeBuilders(t *testing.T) {
+	sub := Select("id").From("docs").Where(Expr("data ?? ?", "tag")).PlaceholderFormat(Dollar)
+	b := Select("*").
+		From("nodes").
+		Where(Expr("data ?? 'key'")).
+		Where(Expr("id IN (?) AND x = ?", sub, 1)).
+		Where("y = ?", 2)
+
+	s, args, err := b.PlaceholderFormat(Question).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM nodes WHERE data ? 'key' AND id IN (SELECT id FROM docs WHERE data ? ?) AND x = ? AND y = ?", s)
+	assert.Equal(t, []interface{}{"tag", 1, 2}, args)
+
+	s, _, err = b.PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM nodes WHERE data ? 'key' AND id IN (SELECT id FROM docs WHERE data ? $1) AND x = $2 AND y = $3", s)
+}
+
+func TestDollarNumberingNestedExprs(t *testing.T) {
+	sub := Select("user_id").
+		From("orders").
+		Where(Expr("created_at > now() - interval ?", "7 days")).
+		Where(Eq{"status": []string{"paid", "sent"}}).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := Select("*").
+		From("users").
+		Where(Eq{"active": true}).
+		Where(Expr("created_at > now() - interval ?", "1 day")).
+		Where(Eq{"id": sub}).
+		Where(Or{Expr("score > ? AND score < ?", 1, 10), Expr("vip = ?", true)}).
+		Where("name ?? ? AND tag = ?", "key", "t").
+		PlaceholderFormat(Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT * FROM users WHERE active = $1 " +
+		"AND created_at > now() - interval $2 " +
+		"AND id IN (SELECT user_id FROM orders WHERE created_at > now() - interval $3 AND status IN ($4,$5)) " +
+		"AND (score > $6 AND score < $7 OR vip = $8) " +
+		"AND name ? $9 AND tag = $10"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{true, "1 day", "7 days", "paid", "sent", 1, 10, true, "key", "t"}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestNamedArgsInEq(t *testing.T) {
+	b := Select("*").From("t").
+		Where(Eq{"a": sql.Named("a", 1)}).
+		Where("b > ? OR c < ?", sql.Named("lim", 2), sql.Named("lim", 2)).
+		Where(Eq{"d": []int{3, 4}})
+
+	s, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE a = ? AND b > ? OR c < ? AND d IN (?,?)", s)
+	assert.Equal(t, []interface{}{sql.Named("a", 1), sql.Named("lim", 2), sql.Named("lim", 2), 3, 4}, args)
+
+	s, args, err = b.PlaceholderFormat(Named).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE a = :a AND b > :lim OR c < :lim AND d IN (:arg3,:arg4)", s)
+	assert.Equal(t, []interface{}{sql.Named("a", 1), sql.Named("lim", 2), sql.Named("arg3", 3), sql.Named("arg4", 4)}, args)
+}
+
+func TestNamedArgsSingleValue(t *testing.T) {
+	s, args, err := Eq{"ids": sql.Named("ids", []int{1, 2})}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ids = ?", s)
+	assert.Equal(t, []interface{}{sql.Named("ids", []int{1, 2})}, args)
+}
+
+func TestNamedArgsConflict(t *testing.T) {
+	_, _, err := Named.ReplacePlaceholdersArgs("a = ? AND b = ?", []interface{}{sql.Named("x", 1), sql.Named("x", 2)})
+	assert.Error(t, err)
+
+	_, _, err = Named.ReplacePlaceholdersArgs("a = ? AND b = ?", []interface{}{1, sql.Named("arg0", 2)})
+	assert.Error(t, err)
+}
+
+// wrapSqlizer is a custom Sqlizer embedding another one
+type wrapSqlizer struct {
+	s Sqlizer
+}
+
+func (w wrapSqlizer) ToSql() (string, []interface{}, error) {
+	s, args, err := NestedToSql(w.s)
+	return "NOT (" + s + ")", args, err
+}
+
+func TestMixedPlaceholderFormatsNesting(t *testing.T) {
+	sub := func(f PlaceholderFormat, col string, v int) *SelectBuilder {
+		return Select(col).From("t").Where(Gt{col: v}).PlaceholderFormat(f)
+	}
+
+	b := Select("a").
+		Column(Alias(sub(Colon, "b", 1), "b")).
+		FromSelect(sub(AtP, "c", 2), "s").
+		JoinLateral(sub(Named, "d", 3), "l").
+		Where(Expr("e IN (?)", sub(Dollar, "e", 4))).
+		Where(Eq{"f": sub(Dollar, "f", 5)}).
+		Where(wrapSqlizer{sub(Dollar, "g", 6)}).
+		Where(Exists(sub(Dollar, "h", 7))).
+		Suffix("LIMIT ?", 8)
+
+	expectedSql := "SELECT a, (SELECT b FROM t WHERE b > $1) AS b " +
+		"FROM (SELECT c FROM t WHERE c > $2) AS s " +
+		"JOIN LATERAL (SELECT d FROM t WHERE d > $3) l ON true " +
+		"WHERE e IN (SELECT e FROM t WHERE e > $4) " +
+		"AND f IN (SELECT f FROM t WHERE f > $5) " +
+		"AND NOT (SELECT g FROM t WHERE g > $6) " +
+		"AND EXISTS (SELECT h FROM t WHERE h > $7) " +
+		"LIMIT $8"
+
+	s, args, err := b.PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSql, s)
+	assert.Equal(t, []interface{}{1, 2, 3, 4, 5, 6, 7, 8}, args)
+
+	s, _, err = b.PlaceholderFormat(Question).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Count(expectedSql, "$"), strings.Count(s, "?"))
+	assert.NotContains(t, s, "$")
+}
+
+func TestNestedToSql(t *testing.T) {
+	sub := Select("a").From("t").Where("b = ?", 1).PlaceholderFormat(Dollar)
+
+	s, args, err := NestedToSql(sub)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE b = ?", s)
+	assert.Equal(t, []interface{}{1}, args)
+
+	s, _, err = NestedToSql(Expr("c = ?", 2))
+	assert.NoError(t, err)
+	assert.Equal(t, "c = ?", s)
+}
+
+func TestInlineSafe(t *testing.T) {
+	type status int
+
+	s, args, err := Select("*").From("t").
+		Where("a = ? AND b = ? AND c = ? AND d = ?", 1, "x", true, status(2)).
+		Where("e - ? > ? AND f = ?", -3, 1.5, []byte("y")).
+		Where("g = ?? AND h = ?", uint8(4)).
+		PlaceholderFormat(InlineSafe(Dollar)).
+		ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE a = 1 AND b = $1 AND c = TRUE AND d = 2 AND e - (-3) > 1.5 AND f = $2 AND g = ? AND h = 4", s)
+	assert.Equal(t, []interface{}{"x", []byte("y")}, args)
+}
+
+func TestInlineSafeKeepsUnsafeArgs(t *testing.T) {
+	now := time.Now()
+	nullInt := sql.NullInt64{Int64: 1, Valid: true}
+	unsafe := []interface{}{"1", now, nullInt, math.NaN(), math.Inf(-1), nil, sql.Named("a", 1), []int{1}}
+
+	s, args, err := InlineSafe(Question).ReplacePlaceholdersArgs(strings.TrimSuffix(strings.Repeat("? ", len(unsafe)), " "), unsafe)
+	assert.NoError(t, err)
+	assert.Equal(t, "? ? ? ? ? ? ? ?", s)
+	assert.Equal(t, len(unsafe), len(args))
+
+	s, args, err = InlineSafe(Named).ReplacePlaceholdersArgs("a = ? AND b = ?", []interface{}{false, "x"})
+	assert.NoError(t, err)
+	assert.Equal(t, "a = FALSE AND b = :arg0", s)
+	assert.Equal(t, []interface{}{sql.Named("arg0", "x")}, args)
+
+	_, _, err = InlineSafe(Named).ReplacePlaceholdersArgs("a = ?", []interface{}{1, "x"})
+	assert.Error(t, err)
+}
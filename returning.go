@@ -16,8 +16,12 @@ func (r *returning) ReturningSelect(from *SelectBuilder, alias string) {
 	*r = append(*r, Alias(from, alias))
 }
 
-func (r *returning) AppendToSql(w io.Writer, args []interface{}) ([]interface{}, error) {
+func (r *returning) AppendToSql(w io.Writer, d Dialect, args []interface{}) ([]interface{}, error) {
+	if !d.supportsReturning() {
+		return nil, errNotSupported("RETURNING clause", d)
+	}
+
 	io.WriteString(w, " RETURNING ")
-	return appendToSql(*r, w, ", ", args)
+	return appendToSql(*r, w, ", ", d, args)
 
 }
@@ -1,5 +1,12 @@
 package sqrl
 
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
 // RowScanner is the interface that wraps the Scan method.
 //
 // Scan behaves like database/sql.Row.Scan.
@@ -20,3 +27,120 @@ func (r *Row) Scan(dest ...interface{}) error {
 	}
 	return r.RowScanner.Scan(dest...)
 }
+
+// ScanOrNoRows is like Scan, but reports the absence of rows as found == false
+// instead of sql.ErrNoRows. Errors of building the query are returned before
+// anything is scanned.
+func (r *Row) ScanOrNoRows(dest ...interface{}) (found bool, err error) {
+	err = r.Scan(dest...)
+	if IsNoRows(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// IsNoRows tells whether err reports that a query returned no rows, that is
+// it is or wraps sql.ErrNoRows.
+func IsNoRows(err error) bool {
+	return errors.Is(err, sql.ErrNoRows)
+}
+
+// RowsScanner is the interface that wraps methods of database/sql.Rows used to
+// scan rows.
+type RowsScanner interface {
+	Columns() ([]string, error)
+	Next() bool
+	Scan(...interface{}) error
+	Err() error
+}
+
+// ScanAll scans all rows into dest, which must be a pointer to a slice of structs
+// or pointers to structs. A new element is appended to the slice for each row.
+//
+// Columns are matched to "db" tags of struct fields, falling back to field
+// names; an error is returned for columns without a field. Nullable columns
+// should be scanned into pointer or sql.Null* fields.
+// Ex:
+//     rows, err := Select("id", "name").From("users").RunWith(db).Query()
+//     ...
+//     defer rows.Close()
+//     var users []User
+//     err = ScanAll(rows, &users)
+func ScanAll(rows RowsScanner, dest interface{}) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("expected pointer to slice of structs, not %T", dest)
+	}
+	slice := dv.Elem()
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("expected pointer to slice of structs, not %T", dest)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	fields, err := fieldsByColumns(structType, columns)
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		elem := reflect.New(structType)
+		if err := scanStruct(rows, elem.Elem(), fields); err != nil {
+			return err
+		}
+		if elemType.Kind() == reflect.Ptr {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+	}
+	return rows.Err()
+}
+
+// ScanOne scans the first row of rows into dest, which must be a pointer to a struct.
+// It returns sql.ErrNoRows if there are no rows.
+//
+// Columns are matched to struct fields the same way as in ScanAll. Unlike
+// Query, QueryRow does not tell columns of the result, so it can't be used here.
+func ScanOne(rows RowsScanner, dest interface{}) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("expected pointer to struct, not %T", dest)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	fields, err := fieldsByColumns(dv.Elem().Type(), columns)
+	if err != nil {
+		return err
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	return scanStruct(rows, dv.Elem(), fields)
+}
+
+func scanStruct(row RowScanner, v reflect.Value, fields []structField) error {
+	dest := make([]interface{}, len(fields))
+	for i, f := range fields {
+		addr, err := structFieldAddr(v, f.index)
+		if err != nil {
+			return err
+		}
+		dest[i] = addr
+	}
+	return row.Scan(dest...)
+}
@@ -1,6 +1,9 @@
 package sqrl
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"testing"
 
@@ -32,3 +35,139 @@ func TestRowScanErr(t *testing.T) {
 	assert.False(t, stub.Scanned, "row was scanned")
 	assert.Equal(t, rowErr, err)
 }
+
+type ScanAudit struct {
+	CreatedBy *string `db:"created_by"`
+}
+
+type scanUser struct {
+	ID       int64 `db:"id"`
+	Name     string
+	Nick     sql.NullString `db:"nick"`
+	Internal string         `db:"-"`
+	*ScanAudit
+}
+
+func TestScanAll(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	query := "SELECT id, name, nick, created_by FROM users"
+	d.setRows(query, []string{"id", "name", "nick", "created_by"},
+		[]driver.Value{int64(1), "moe", nil, "admin"},
+		[]driver.Value{int64(2), "larry", "lar", nil},
+	)
+
+	rows, err := db.Query(query)
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	var users []scanUser
+	assert.NoError(t, ScanAll(rows, &users))
+
+	admin := "admin"
+	expected := []scanUser{
+		{ID: 1, Name: "moe", ScanAudit: &ScanAudit{CreatedBy: &admin}},
+		{ID: 2, Name: "larry", Nick: sql.NullString{String: "lar", Valid: true}, ScanAudit: &ScanAudit{}},
+	}
+	assert.Equal(t, expected, users)
+
+	rows, err = db.Query(query)
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	var pointers []*scanUser
+	assert.NoError(t, ScanAll(rows, &pointers))
+	assert.Len(t, pointers, 2)
+	assert.Equal(t, "larry", pointers[1].Name)
+}
+
+func TestScanAllErr(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	query := "SELECT id, email FROM users"
+	d.setRows(query, []string{"id", "email"}, []driver.Value{int64(1), "moe@example.com"})
+
+	rows, err := db.Query(query)
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	var users []scanUser
+	assert.EqualError(t, ScanAll(rows, &users), `column "email" is not mapped to a field of sqrl.scanUser`)
+	assert.Error(t, ScanAll(rows, users))
+	assert.Error(t, ScanAll(rows, &[]int{}))
+}
+
+func TestScanOne(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	query := "SELECT id, name FROM users"
+	d.setRows(query, []string{"id", "name"}, []driver.Value{int64(7), "curly"})
+
+	rows, err := db.Query(query)
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	var user scanUser
+	assert.NoError(t, ScanOne(rows, &user))
+	assert.Equal(t, scanUser{ID: 7, Name: "curly"}, user)
+
+	rows, err = db.Query("SELECT id FROM nobody")
+	assert.NoError(t, err)
+	defer rows.Close()
+	assert.Equal(t, sql.ErrNoRows, ScanOne(rows, &user))
+
+	assert.Error(t, ScanOne(rows, user))
+}
+
+// noRowsScanner is a RowScanner of a query without rows
+type noRowsScanner struct {
+	scanned bool
+}
+
+func (r *noRowsScanner) Scan(_ ...interface{}) error {
+	r.scanned = true
+	return fmt.Errorf("scan user: %w", sql.ErrNoRows)
+}
+
+func TestRowScanOrNoRows(t *testing.T) {
+	found, err := (&Row{RowScanner: &RowStub{}}).ScanOrNoRows()
+	assert.True(t, found)
+	assert.NoError(t, err)
+
+	stub := &noRowsScanner{}
+	found, err = (&Row{RowScanner: stub}).ScanOrNoRows()
+	assert.True(t, stub.scanned)
+	assert.False(t, found)
+	assert.NoError(t, err)
+
+	rowErr := fmt.Errorf("build err")
+	stub = &noRowsScanner{}
+	found, err = (&Row{RowScanner: stub, err: rowErr}).ScanOrNoRows()
+	assert.False(t, stub.scanned)
+	assert.False(t, found)
+	assert.Equal(t, rowErr, err)
+}
+
+func TestIsNoRows(t *testing.T) {
+	assert.True(t, IsNoRows(sql.ErrNoRows))
+	assert.True(t, IsNoRows(fmt.Errorf("wrapped: %w", sql.ErrNoRows)))
+	assert.False(t, IsNoRows(fmt.Errorf("other")))
+	assert.False(t, IsNoRows(nil))
+}
+
+func TestQueryRowWithToSqlErrNotRun(t *testing.T) {
+	db := &DBStub{}
+
+	found, err := QueryRowWith(db, Select()).(*Row).ScanOrNoRows()
+	assert.False(t, found)
+	assert.Error(t, err)
+	assert.False(t, IsNoRows(err))
+	assert.Empty(t, db.LastQueryRowSql, "query must not run when ToSql fails")
+
+	err = QueryRowWithContext(context.Background(), db, Select()).Scan()
+	assert.Error(t, err)
+	assert.Empty(t, db.LastQueryRowSql)
+}
@@ -0,0 +1,75 @@
+package sqrl
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+)
+
+// Sanitizer normalizes SQL of a statement before it is run, e.g. to satisfy a
+// driver which rejects some syntax. See StatementBuilderType.Sanitize.
+type Sanitizer func(sql string) string
+
+// Sanitizers combines sanitizers into one running them in the given order.
+func Sanitizers(sanitizers ...Sanitizer) Sanitizer {
+	return func(sql string) string {
+		for _, s := range sanitizers {
+			sql = s(sql)
+		}
+		return sql
+	}
+}
+
+// TrimSemicolon is a Sanitizer removing trailing semicolons and whitespace,
+// e.g. added by Suffix.
+func TrimSemicolon(sql string) string {
+	return strings.TrimRightFunc(sql, func(r rune) bool {
+		return r == ';' || unicode.IsSpace(r)
+	})
+}
+
+// CollapseWhitespace is a Sanitizer replacing each run of whitespace, like new
+// lines and indentation of multiline expressions, with a single space and
+// trimming it at both ends. Quoted strings and identifiers and comments are
+// kept as is, as well as the new line ending a "--" comment.
+func CollapseWhitespace(sql string) string {
+	sql = strings.TrimSpace(sql)
+	buf := &bytes.Buffer{}
+	space, lineEnd := false, false
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v' {
+			space = !lineEnd
+			i++
+			continue
+		}
+
+		if space {
+			buf.WriteByte(' ')
+		}
+		space, lineEnd = false, false
+
+		end := i + 1
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end = spanEnd(sql, i+1, string(c))
+		case strings.HasPrefix(sql[i:], "/*"):
+			end = spanEnd(sql, i+2, "*/")
+		case strings.HasPrefix(sql[i:], "--"):
+			end = spanEnd(sql, i+2, "\n")
+			lineEnd = true
+		}
+		buf.WriteString(sql[i:end])
+		i = end
+	}
+	return buf.String()
+}
+
+// spanEnd returns the index in sql right after the first terminator found
+// from index from, or len(sql) if there is none.
+func spanEnd(sql string, from int, terminator string) int {
+	if p := strings.Index(sql[from:], terminator); p >= 0 {
+		return from + p + len(terminator)
+	}
+	return len(sql)
+}
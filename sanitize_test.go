@@ -0,0 +1,61 @@
+package sqrl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrimSemicolon(t *testing.T) {
+	assert.Equal(t, "SELECT 1", TrimSemicolon("SELECT 1"))
+	assert.Equal(t, "SELECT 1", TrimSemicolon("SELECT 1;"))
+	assert.Equal(t, "SELECT 1", TrimSemicolon("SELECT 1 ; ;\n"))
+	assert.Equal(t, "SELECT ';'", TrimSemicolon("SELECT ';';"))
+}
+
+func TestCollapseWhitespace(t *testing.T) {
+	assert.Equal(t, "SELECT a, b FROM t WHERE c = ?", CollapseWhitespace("\n  SELECT a,\n\tb FROM   t\n  WHERE c = ?  \n"))
+	assert.Equal(t, `SELECT 'a  b', "c  d", `+"`e  f`"+` FROM t`, CollapseWhitespace(`SELECT  'a  b',  "c  d",  `+"`e  f`"+`  FROM t`))
+	assert.Equal(t, "SELECT 'it''s  ok' FROM t", CollapseWhitespace("SELECT  'it''s  ok'  FROM t"))
+}
+
+func TestCollapseWhitespaceComments(t *testing.T) {
+	assert.Equal(t, "-- job: report\nSELECT a FROM t", CollapseWhitespace("-- job: report\n  SELECT a\n  FROM t"))
+	assert.Equal(t, "SELECT a /* x\n   y */ FROM t -- end", CollapseWhitespace("SELECT a  /* x\n   y */\n FROM t -- end\n"))
+	assert.Equal(t, "SELECT '--' AS a FROM t", CollapseWhitespace("SELECT '--'  AS a\nFROM t"))
+
+	sql, _, err := StatementBuilder.Sanitize(CollapseWhitespace).
+		Select("a").From("t").Where("b = ?", 1).
+		Prefix("-- job: report\n").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "-- job: report\nSELECT a FROM t WHERE b = ?", sql)
+}
+
+func TestSanitizers(t *testing.T) {
+	s := Sanitizers(CollapseWhitespace, TrimSemicolon)
+	assert.Equal(t, "SELECT a FROM t", s("SELECT a\n  FROM t ;\n"))
+
+	upper := Sanitizers(s, strings.ToUpper)
+	assert.Equal(t, "SELECT A FROM T", upper("select a\nfrom t;"))
+
+	assert.Equal(t, "SELECT 1 ", Sanitizers()("SELECT 1 "))
+}
+
+func TestStatementBuilderSanitize(t *testing.T) {
+	sb := StatementBuilder.PlaceholderFormat(Dollar).Sanitize(TrimSemicolon, CollapseWhitespace)
+
+	sql, args, err := sb.Select("a").
+		From("t").
+		Where("b = ?\n  AND c = ?", 1, 2).
+		Suffix("/* app */;").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE b = $1 AND c = $2 /* app */", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, _, err = StatementBuilder.Select("a").From("t").Suffix(";").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t ;", sql)
+}
@@ -5,30 +5,42 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"strconv"
 	"strings"
 )
 
 // SelectBuilder builds SQL SELECT statements.
+//
+// Clauses are always rendered in the same order, whatever order the methods
+// were called in: prefixes, WITH, SELECT, FROM, JOIN, WHERE, GROUP BY, HAVING,
+// WINDOW, ORDER BY, LIMIT, OFFSET, locking clause and suffixes.
 type SelectBuilder struct {
 	StatementBuilderType
 
+	with
+
 	prefixes    exprs
 	distinct    bool
+	distinctOn  []string
 	options     []string
 	columns     []Sqlizer
 	fromParts   []Sqlizer
 	joins       []Sqlizer
 	whereParts  []Sqlizer
 	groupBys    []string
+	rollup      []string
 	havingParts []Sqlizer
-	orderBys    []string
+	windows     []Sqlizer
+	orderBys    []Sqlizer
 
 	limit       uint64
 	limitValid  bool
 	offset      uint64
 	offsetValid bool
 
+	lockStrength string
+	lockOf       []string
+	lockWait     string
+
 	suffixes exprs
 }
 
@@ -43,9 +55,18 @@ func (b *SelectBuilder) RunWith(runner BaseRunner) *SelectBuilder {
 	return b
 }
 
+// WithContext sets ctx to be used by Exec, Query, QueryRow and Scan, which do not take a context.
+//
+// Like the other methods, it changes the builder and returns it, so the context
+// is kept by the builder: set it on builders made for a single request only.
+func (b *SelectBuilder) WithContext(ctx context.Context) *SelectBuilder {
+	b.ctx = ctx
+	return b
+}
+
 // Exec builds and Execs the query with the Runner set by RunWith.
 func (b *SelectBuilder) Exec() (sql.Result, error) {
-	return b.ExecContext(context.Background())
+	return b.ExecContext(b.runContext())
 }
 
 // ExecContext builds and Execs the query with the Runner set by RunWith using given context.
@@ -58,7 +79,7 @@ func (b *SelectBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
 
 // Query builds and Querys the query with the Runner set by RunWith.
 func (b *SelectBuilder) Query() (*sql.Rows, error) {
-	return b.QueryContext(context.Background())
+	return b.QueryContext(b.runContext())
 }
 
 // QueryContext builds and Querys the query with the Runner set by RunWith in given context.
@@ -71,7 +92,7 @@ func (b *SelectBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
 
 // QueryRow builds and QueryRows the query with the Runner set by RunWith.
 func (b *SelectBuilder) QueryRow() RowScanner {
-	return b.QueryRowContext(context.Background())
+	return b.QueryRowContext(b.runContext())
 }
 
 func (b *SelectBuilder) QueryRowContext(ctx context.Context) RowScanner {
@@ -97,33 +118,75 @@ func (b *SelectBuilder) PlaceholderFormat(f PlaceholderFormat) *SelectBuilder {
 	return b
 }
 
+// Dialect sets Dialect (e.g. PostgresDialect or MySQLDialect) for the query.
+// PlaceholderFormat is set to the one used by the dialect.
+func (b *SelectBuilder) Dialect(d Dialect) *SelectBuilder {
+	b.dialect = d
+	b.placeholderFormat = d.PlaceholderFormat()
+	return b
+}
+
 // ToSql builds the query into a SQL string and bound args.
-func (b *SelectBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+func (b *SelectBuilder) ToSql() (string, []interface{}, error) {
+	sql, args, err := b.toSqlRaw()
+	if err != nil {
+		return "", nil, err
+	}
+	return b.formatSql(sql, args)
+}
+
+// MustSql builds the query like ToSql and panics on error, see MustSql.
+func (b *SelectBuilder) MustSql() (string, []interface{}) {
+	return MustSql(b)
+}
+
+// toSqlRaw builds the query leaving placeholders as is.
+func (b *SelectBuilder) toSqlRaw() (sqlStr string, args []interface{}, err error) {
 	if len(b.columns) == 0 {
 		err = fmt.Errorf("select statements must have at least one result column")
 		return
 	}
+	if b.distinct && len(b.distinctOn) > 0 {
+		err = fmt.Errorf("select statements cannot have both DISTINCT and DISTINCT ON clauses")
+		return
+	}
 
 	sql := &bytes.Buffer{}
 
 	if len(b.prefixes) > 0 {
-		args, _ = b.prefixes.AppendToSql(sql, " ", args)
+		args, err = b.prefixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
+	if len(b.ctes) > 0 {
+		args, err = b.with.AppendToSql(sql, args)
+		if err != nil {
+			return
+		}
+	}
+
 	sql.WriteString("SELECT ")
 
 	if b.distinct {
 		sql.WriteString("DISTINCT ")
 	}
 
+	if len(b.distinctOn) > 0 {
+		sql.WriteString("DISTINCT ON (")
+		sql.WriteString(strings.Join(b.distinctOn, ", "))
+		sql.WriteString(") ")
+	}
+
 	if len(b.options) > 0 {
 		sql.WriteString(strings.Join(b.options, " "))
 		sql.WriteString(" ")
 	}
 
 	if len(b.columns) > 0 {
-		args, err = appendToSql(b.columns, sql, ", ", args)
+		args, err = appendToSql(b.columns, sql, ", ", b.exprDialect(), args)
 		if err != nil {
 			return
 		}
@@ -131,7 +194,7 @@ func (b *SelectBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.fromParts) > 0 {
 		sql.WriteString(" FROM ")
-		args, err = appendToSql(b.fromParts, sql, ", ", args)
+		args, err = appendToSql(b.fromParts, sql, ", ", b.exprDialect(), args)
 		if err != nil {
 			return
 		}
@@ -139,7 +202,7 @@ func (b *SelectBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.joins) > 0 {
 		sql.WriteString(" ")
-		args, err = appendToSql(b.joins, sql, " ", args)
+		args, err = appendToSql(b.joins, sql, " ", b.exprDialect(), args)
 		if err != nil {
 			return
 		}
@@ -147,20 +210,31 @@ func (b *SelectBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.whereParts) > 0 {
 		sql.WriteString(" WHERE ")
-		args, err = appendToSql(b.whereParts, sql, " AND ", args)
+		args, err = appendToSql(b.whereParts, sql, " AND ", b.exprDialect(), args)
 		if err != nil {
 			return
 		}
 	}
 
-	if len(b.groupBys) > 0 {
+	if len(b.groupBys) > 0 || len(b.rollup) > 0 {
 		sql.WriteString(" GROUP BY ")
-		sql.WriteString(strings.Join(b.groupBys, ", "))
+		err = b.appendGroupBy(sql)
+		if err != nil {
+			return
+		}
 	}
 
 	if len(b.havingParts) > 0 {
 		sql.WriteString(" HAVING ")
-		args, err = appendToSql(b.havingParts, sql, " AND ", args)
+		args, err = appendToSql(b.havingParts, sql, " AND ", b.exprDialect(), args)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(b.windows) > 0 {
+		sql.WriteString(" WINDOW ")
+		args, err = appendToSql(b.windows, sql, ", ", b.exprDialect(), args)
 		if err != nil {
 			return
 		}
@@ -168,31 +242,116 @@ func (b *SelectBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.orderBys) > 0 {
 		sql.WriteString(" ORDER BY ")
-		sql.WriteString(strings.Join(b.orderBys, ", "))
+		args, err = appendToSql(b.orderBys, sql, ", ", b.exprDialect(), args)
+		if err != nil {
+			return
+		}
 	}
 
-	// TODO: limit == 0 and offswt == 0 are valid. Need to go dbr way and implement offsetValid and limitValid
-	if b.limitValid {
-		sql.WriteString(" LIMIT ")
-		sql.WriteString(strconv.FormatUint(b.limit, 10))
+	args, err = appendLimitOffset(sql, b.dialect, len(b.orderBys) > 0, b.limit, b.limitValid, b.offset, b.offsetValid, args)
+	if err != nil {
+		return
 	}
 
-	if b.offsetValid {
-		sql.WriteString(" OFFSET ")
-		sql.WriteString(strconv.FormatUint(b.offset, 10))
+	if len(b.lockStrength) > 0 {
+		sql.WriteString(" FOR ")
+		sql.WriteString(b.lockStrength)
+		if len(b.lockOf) > 0 {
+			sql.WriteString(" OF ")
+			sql.WriteString(strings.Join(b.lockOf, ", "))
+		}
+		if len(b.lockWait) > 0 {
+			sql.WriteString(" ")
+			sql.WriteString(b.lockWait)
+		}
+	} else if len(b.lockOf) > 0 || len(b.lockWait) > 0 {
+		err = fmt.Errorf("locking options require FOR UPDATE or FOR SHARE clause")
+		return
 	}
 
 	if len(b.suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = b.suffixes.AppendToSql(sql, " ", args)
+		args, err = b.suffixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 	}
 
-	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	sqlStr = sql.String()
 	return
 
 }
 
-// Prefix adds an expression to the beginning of the query
+// checkDmlLimitOffset rejects LIMIT and OFFSET of UPDATE and DELETE statements for
+// dialects paging with "OFFSET ... FETCH", which is valid in SELECT only.
+func checkDmlLimitOffset(stmt string, d Dialect, limitValid, offsetValid bool) error {
+	if (limitValid || offsetValid) && (d == SQLServerDialect || d == OracleDialect) {
+		return errNotSupported("LIMIT and OFFSET of "+stmt, d)
+	}
+	return nil
+}
+
+// appendLimitOffset writes LIMIT and OFFSET clauses in the form of the dialect:
+// "LIMIT ? OFFSET ?" by default, "OFFSET ? ROWS FETCH NEXT ? ROWS ONLY" for
+// SQL Server, which also requires ORDER BY there, and Oracle.
+//
+// Values are bound as args, so queries differing only in pagination are the same
+// prepared statement.
+func appendLimitOffset(sql *bytes.Buffer, d Dialect, hasOrderBy bool, limit uint64, limitValid bool, offset uint64, offsetValid bool, args []interface{}) ([]interface{}, error) {
+	if !limitValid && !offsetValid {
+		return args, nil
+	}
+
+	switch d {
+	case SQLServerDialect, OracleDialect:
+		if d == SQLServerDialect && !hasOrderBy {
+			return nil, fmt.Errorf("LIMIT and OFFSET require ORDER BY clause for %s dialect", d)
+		}
+		if offsetValid || d == SQLServerDialect {
+			sql.WriteString(" OFFSET ? ROWS")
+			args = append(args, offset)
+		}
+		if limitValid {
+			if offsetValid || d == SQLServerDialect {
+				sql.WriteString(" FETCH NEXT ? ROWS ONLY")
+			} else {
+				sql.WriteString(" FETCH FIRST ? ROWS ONLY")
+			}
+			args = append(args, limit)
+		}
+	default:
+		if limitValid {
+			sql.WriteString(" LIMIT ?")
+			args = append(args, limit)
+		}
+		if offsetValid {
+			sql.WriteString(" OFFSET ?")
+			args = append(args, offset)
+		}
+	}
+	return args, nil
+}
+
+// With adds a common table expression to WITH clause of the query:
+// "WITH name AS (query) SELECT ...".
+//
+// The name is written verbatim, so column list could be given as well, e.g. "t(a, b)".
+func (b *SelectBuilder) With(name string, query Sqlizer) *SelectBuilder {
+	b.with.With(name, query)
+	return b
+}
+
+// WithRecursive adds a common table expression to WITH clause of the query,
+// making the clause "WITH RECURSIVE".
+func (b *SelectBuilder) WithRecursive(name string, query Sqlizer) *SelectBuilder {
+	b.with.WithRecursive(name, query)
+	return b
+}
+
+// Prefix adds an expression to the beginning of the query.
+//
+// Sqlizer args are embedded in place of their placeholders along with their
+// args, e.g. Prefix("WITH t AS (?)", Select("id").From("a").Where("b = ?", 1)).
 func (b *SelectBuilder) Prefix(sql string, args ...interface{}) *SelectBuilder {
 	b.prefixes = append(b.prefixes, Expr(sql, args...))
 	return b
@@ -205,6 +364,15 @@ func (b *SelectBuilder) Distinct() *SelectBuilder {
 	return b
 }
 
+// DistinctOn adds a DISTINCT ON (...) clause to the query.
+// Columns are written verbatim, so expressions could be used as well.
+//
+// SELECT DISTINCT ON is PostgreSQL specific extension
+func (b *SelectBuilder) DistinctOn(columns ...string) *SelectBuilder {
+	b.distinctOn = append(b.distinctOn, columns...)
+	return b
+}
+
 // Options adds select option to the query
 func (b *SelectBuilder) Options(options ...string) *SelectBuilder {
 	for _, str := range options {
@@ -232,6 +400,14 @@ func (b *SelectBuilder) Column(column interface{}, args ...interface{}) *SelectB
 	return b
 }
 
+// ColumnIf adds a result column like Column does, but only if cond is true.
+func (b *SelectBuilder) ColumnIf(cond bool, column interface{}, args ...interface{}) *SelectBuilder {
+	if cond {
+		b.Column(column, args...)
+	}
+	return b
+}
+
 // From sets the FROM clause of the query.
 func (b *SelectBuilder) From(tables ...string) *SelectBuilder {
 	parts := make([]Sqlizer, len(tables))
@@ -249,6 +425,75 @@ func (b *SelectBuilder) FromSelect(from *SelectBuilder, alias string) *SelectBui
 	return b
 }
 
+// FromValues sets a VALUES list into the FROM clause of the query:
+// "SELECT * FROM (VALUES (?, ?)) AS t(id, name)".
+//
+// Column names are set with ValuesBuilder.Columns.
+func (b *SelectBuilder) FromValues(values *ValuesBuilder, alias string) *SelectBuilder {
+	b.fromParts = append(b.fromParts, fromValuesPart{values: values, alias: alias})
+	return b
+}
+
+// UseIndex adds "USE INDEX (indexes)" hint to the last table of FROM clause.
+//
+// Index hints are MySQL specific, ToSql returns an error for other dialects.
+func (b *SelectBuilder) UseIndex(indexes ...string) *SelectBuilder {
+	return b.indexHint("USE INDEX", indexes)
+}
+
+// ForceIndex adds "FORCE INDEX (indexes)" hint to the last table of FROM clause.
+func (b *SelectBuilder) ForceIndex(indexes ...string) *SelectBuilder {
+	return b.indexHint("FORCE INDEX", indexes)
+}
+
+// IgnoreIndex adds "IGNORE INDEX (indexes)" hint to the last table of FROM clause.
+func (b *SelectBuilder) IgnoreIndex(indexes ...string) *SelectBuilder {
+	return b.indexHint("IGNORE INDEX", indexes)
+}
+
+func (b *SelectBuilder) indexHint(hint string, indexes []string) *SelectBuilder {
+	hint = fmt.Sprintf("%s (%s)", hint, strings.Join(indexes, ", "))
+	if len(b.fromParts) == 0 {
+		b.fromParts = append(b.fromParts, &indexHintsPart{hints: []string{hint}})
+		return b
+	}
+
+	last := len(b.fromParts) - 1
+	if p, ok := b.fromParts[last].(*indexHintsPart); ok {
+		// the part could be shared with a Clone, so it is replaced, not changed
+		hints := append(p.hints[:len(p.hints):len(p.hints)], hint)
+		b.fromParts[last] = &indexHintsPart{table: p.table, hints: hints}
+	} else {
+		b.fromParts[last] = &indexHintsPart{table: b.fromParts[last], hints: []string{hint}}
+	}
+	return b
+}
+
+// indexHintsPart is a table of FROM clause followed by MySQL index hints
+type indexHintsPart struct {
+	table Sqlizer
+	hints []string
+}
+
+func (p *indexHintsPart) ToSql() (string, []interface{}, error) {
+	return p.toSqlDialect(NoDialect)
+}
+
+func (p *indexHintsPart) toSqlDialect(d Dialect) (string, []interface{}, error) {
+	if d != MySQLDialect {
+		return "", nil, errNotSupported("index hint", d)
+	}
+	if p.table == nil {
+		return "", nil, fmt.Errorf("index hints require a table in FROM clause")
+	}
+
+	sql, args, err := dialectToSql(p.table, d)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s %s", sql, strings.Join(p.hints, " ")), args, nil
+}
+
 // JoinClause adds a join clause to the query.
 func (b *SelectBuilder) JoinClause(pred interface{}, args ...interface{}) *SelectBuilder {
 	b.joins = append(b.joins, newPart(pred, args...))
@@ -257,18 +502,138 @@ func (b *SelectBuilder) JoinClause(pred interface{}, args ...interface{}) *Selec
 }
 
 // Join adds a JOIN clause to the query.
+//
+// The join condition could be a part of join string with args bound to its
+// placeholders, or a single Sqlizer following join string without placeholders:
+//     .Join("orders o", And{Eq{"o.user_id": Expr("u.id")}, Gt{"o.total": 100}})
+//     == "JOIN orders o ON (o.user_id = u.id AND o.total > ?)"
+// The Sqlizer condition is wrapped in parentheses unless it is And or Or,
+// which are already wrapped, so joins could be chained or followed by other
+// clauses safely. The same applies to the other joins.
 func (b *SelectBuilder) Join(join string, rest ...interface{}) *SelectBuilder {
-	return b.JoinClause("JOIN "+join, rest...)
+	b.joins = append(b.joins, newJoinPart("JOIN "+join, rest))
+	return b
 }
 
 // LeftJoin adds a LEFT JOIN clause to the query.
 func (b *SelectBuilder) LeftJoin(join string, rest ...interface{}) *SelectBuilder {
-	return b.JoinClause("LEFT JOIN "+join, rest...)
+	b.joins = append(b.joins, newJoinPart("LEFT JOIN "+join, rest))
+	return b
 }
 
 // RightJoin adds a RIGHT JOIN clause to the query.
 func (b *SelectBuilder) RightJoin(join string, rest ...interface{}) *SelectBuilder {
-	return b.JoinClause("RIGHT JOIN "+join, rest...)
+	b.joins = append(b.joins, newJoinPart("RIGHT JOIN "+join, rest))
+	return b
+}
+
+// FullJoin adds a FULL JOIN clause to the query.
+func (b *SelectBuilder) FullJoin(join string, rest ...interface{}) *SelectBuilder {
+	b.joins = append(b.joins, newJoinPart("FULL JOIN "+join, rest))
+	return b
+}
+
+// CrossJoin adds a CROSS JOIN clause to the query.
+//
+// CROSS JOIN has no join condition, so ToSql returns an error if args are given.
+func (b *SelectBuilder) CrossJoin(join string, rest ...interface{}) *SelectBuilder {
+	b.joins = append(b.joins, crossJoinPart{join: join, args: rest})
+	return b
+}
+
+// newJoinPart makes a join clause, see SelectBuilder.Join.
+func newJoinPart(join string, rest []interface{}) Sqlizer {
+	if len(rest) == 1 && !strings.Contains(join, "?") {
+		if on, ok := rest[0].(Sqlizer); ok {
+			return joinOnPart{join: join, on: on}
+		}
+	}
+	return newPart(join, rest...)
+}
+
+// joinOnPart is a join clause with a Sqlizer condition: "JOIN t ON (cond)"
+type joinOnPart struct {
+	join string
+	on   Sqlizer
+}
+
+func (p joinOnPart) ToSql() (string, []interface{}, error) {
+	return p.toSqlDialect(NoDialect)
+}
+
+func (p joinOnPart) toSqlDialect(d Dialect) (string, []interface{}, error) {
+	sql, args, err := dialectToSql(p.on, d)
+	if err != nil {
+		return "", nil, err
+	}
+	switch p.on.(type) {
+	case And, Or:
+		return fmt.Sprintf("%s ON %s", p.join, sql), args, nil
+	}
+	return fmt.Sprintf("%s ON (%s)", p.join, sql), args, nil
+}
+
+type crossJoinPart struct {
+	join string
+	args []interface{}
+}
+
+func (p crossJoinPart) ToSql() (string, []interface{}, error) {
+	if len(p.args) > 0 {
+		return "", nil, fmt.Errorf("CROSS JOIN %s does not accept args, got %d", p.join, len(p.args))
+	}
+	return "CROSS JOIN " + p.join, nil, nil
+}
+
+// JoinLateral adds a "JOIN LATERAL (query) alias ON ..." clause to the query.
+//
+// The optional on argument is a join condition followed by its args, it
+// accepts the same types as Where. The condition defaults to "true".
+func (b *SelectBuilder) JoinLateral(query Sqlizer, alias string, on ...interface{}) *SelectBuilder {
+	b.joins = append(b.joins, newLateralJoinPart("JOIN", query, alias, on))
+	return b
+}
+
+// LeftJoinLateral adds a "LEFT JOIN LATERAL (query) alias ON ..." clause to the query.
+//
+// See JoinLateral.
+func (b *SelectBuilder) LeftJoinLateral(query Sqlizer, alias string, on ...interface{}) *SelectBuilder {
+	b.joins = append(b.joins, newLateralJoinPart("LEFT JOIN", query, alias, on))
+	return b
+}
+
+type lateralJoinPart struct {
+	join  string
+	query Sqlizer
+	alias string
+	on    Sqlizer
+}
+
+func newLateralJoinPart(join string, query Sqlizer, alias string, on []interface{}) lateralJoinPart {
+	p := lateralJoinPart{join: join, query: query, alias: alias}
+	if len(on) > 0 {
+		p.on = newWherePart(on[0], on[1:]...)
+	}
+	return p
+}
+
+func (p lateralJoinPart) ToSql() (string, []interface{}, error) {
+	querySql, args, err := nestedToSql(p.query)
+	if err != nil {
+		return "", nil, err
+	}
+
+	onSql := "true"
+	if p.on != nil {
+		var onArgs []interface{}
+		onSql, onArgs, err = p.on.ToSql()
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, onArgs...)
+	}
+
+	return fmt.Sprintf("%s LATERAL (%s) %s ON %s", p.join, querySql, p.alias, onSql), args, nil
 }
 
 // Where adds an expression to the WHERE clause of the query.
@@ -296,12 +661,52 @@ func (b *SelectBuilder) Where(pred interface{}, args ...interface{}) *SelectBuil
 	return b
 }
 
+// WhereIf adds WHERE expressions like Where does, but only if cond is true.
+// Ex:
+//     .WhereIf(name != "", "name = ?", name)
+func (b *SelectBuilder) WhereIf(cond bool, pred interface{}, args ...interface{}) *SelectBuilder {
+	if cond {
+		b.Where(pred, args...)
+	}
+	return b
+}
+
 // GroupBy adds GROUP BY expressions to the query.
 func (b *SelectBuilder) GroupBy(groupBys ...string) *SelectBuilder {
 	b.groupBys = append(b.groupBys, groupBys...)
 	return b
 }
 
+// GroupByRollup adds columns to ROLLUP grouping of the query, producing
+// subtotal rows. Postgres and most databases get "GROUP BY ROLLUP(a, b)",
+// MySQL dialect gets "GROUP BY a, b WITH ROLLUP".
+//
+// Columns added with GroupBy are rendered in front of the rollup ones.
+func (b *SelectBuilder) GroupByRollup(columns ...string) *SelectBuilder {
+	b.rollup = append(b.rollup, columns...)
+	return b
+}
+
+func (b *SelectBuilder) appendGroupBy(sql *bytes.Buffer) error {
+	groupBys := b.groupBys
+	if len(b.rollup) > 0 {
+		switch b.dialect {
+		case SQLiteDialect:
+			return errNotSupported("ROLLUP", b.dialect)
+		case MySQLDialect:
+			groupBys = append(groupBys[:len(groupBys):len(groupBys)], b.rollup...)
+		default:
+			groupBys = append(groupBys[:len(groupBys):len(groupBys)], "ROLLUP("+strings.Join(b.rollup, ", ")+")")
+		}
+	}
+
+	sql.WriteString(strings.Join(groupBys, ", "))
+	if len(b.rollup) > 0 && b.dialect == MySQLDialect {
+		sql.WriteString(" WITH ROLLUP")
+	}
+	return nil
+}
+
 // Having adds an expression to the HAVING clause of the query.
 //
 // See Where.
@@ -310,9 +715,36 @@ func (b *SelectBuilder) Having(pred interface{}, rest ...interface{}) *SelectBui
 	return b
 }
 
+// Window adds a named window definition to WINDOW clause of the query.
+// Columns can refer to the window with OverWindow.
+// Ex:
+//     .Column(OverWindow("rank()", "w")).Window("w", PartitionBy("dept"), OrderBy("salary DESC"))
+func (b *SelectBuilder) Window(name string, parts ...Sqlizer) *SelectBuilder {
+	b.windows = append(b.windows, namedWindow{name: name, spec: parts})
+	return b
+}
+
 // OrderBy adds ORDER BY expressions to the query.
 func (b *SelectBuilder) OrderBy(orderBys ...string) *SelectBuilder {
-	b.orderBys = append(b.orderBys, orderBys...)
+	for _, orderBy := range orderBys {
+		b.orderBys = append(b.orderBys, newPart(orderBy))
+	}
+	return b
+}
+
+// OrderByIf adds ORDER BY expressions like OrderBy does, but only if cond is true.
+func (b *SelectBuilder) OrderByIf(cond bool, orderBys ...string) *SelectBuilder {
+	if cond {
+		b.OrderBy(orderBys...)
+	}
+	return b
+}
+
+// OrderByClause adds ORDER BY expression with args bound to its placeholders,
+// e.g. OrderByClause("FIELD(id, ?, ?)", 3, 1). It could be mixed freely with
+// OrderBy, the expressions keep the order they were added in.
+func (b *SelectBuilder) OrderByClause(pred interface{}, args ...interface{}) *SelectBuilder {
+	b.orderBys = append(b.orderBys, newPart(pred, args...))
 	return b
 }
 
@@ -330,9 +762,184 @@ func (b *SelectBuilder) Offset(offset uint64) *SelectBuilder {
 	return b
 }
 
-// Suffix adds an expression to the end of the query
+// Clone returns a copy of the builder, so parts could be added to or removed
+// from the copy without changing the original query. Sqlizers added to the
+// query, e.g. subqueries, are shared by both builders.
+func (b *SelectBuilder) Clone() *SelectBuilder {
+	c := *b
+	c.with = b.with.clone()
+	c.prefixes = append(exprs(nil), b.prefixes...)
+	c.distinctOn = cloneStrings(b.distinctOn)
+	c.options = cloneStrings(b.options)
+	c.columns = cloneSqlizers(b.columns)
+	c.fromParts = cloneSqlizers(b.fromParts)
+	c.joins = cloneSqlizers(b.joins)
+	c.whereParts = cloneSqlizers(b.whereParts)
+	c.groupBys = cloneStrings(b.groupBys)
+	c.rollup = cloneStrings(b.rollup)
+	c.havingParts = cloneSqlizers(b.havingParts)
+	c.windows = cloneSqlizers(b.windows)
+	c.orderBys = cloneSqlizers(b.orderBys)
+	c.lockOf = cloneStrings(b.lockOf)
+	c.suffixes = append(exprs(nil), b.suffixes...)
+	return &c
+}
+
+// RemoveColumns removes result columns from the query, e.g. to replace them
+// with "COUNT(*)" when counting all rows of a paginated query:
+//     count := q.Clone().RemoveColumns().Column("COUNT(*)").RemoveOrderBys().RemoveLimit().RemoveOffset()
+//
+// Like the other methods, it changes the builder and returns it, use Clone to
+// keep the original query.
+func (b *SelectBuilder) RemoveColumns() *SelectBuilder {
+	b.columns = nil
+	return b
+}
+
+// RemoveWhere removes all WHERE expressions from the query.
+func (b *SelectBuilder) RemoveWhere() *SelectBuilder {
+	b.whereParts = nil
+	return b
+}
+
+// RemoveOrderBys removes ORDER BY clause from the query.
+func (b *SelectBuilder) RemoveOrderBys() *SelectBuilder {
+	b.orderBys = nil
+	return b
+}
+
+// RemoveLimit removes LIMIT clause from the query, e.g. to count all rows
+// of a paginated query.
+func (b *SelectBuilder) RemoveLimit() *SelectBuilder {
+	b.limit = 0
+	b.limitValid = false
+	return b
+}
+
+// RemoveOffset removes OFFSET clause from the query.
+func (b *SelectBuilder) RemoveOffset() *SelectBuilder {
+	b.offset = 0
+	b.offsetValid = false
+	return b
+}
+
+// ForUpdate adds a FOR UPDATE locking clause to the query.
+func (b *SelectBuilder) ForUpdate() *SelectBuilder {
+	b.lockStrength = "UPDATE"
+	return b
+}
+
+// ForShare adds a FOR SHARE locking clause to the query.
+func (b *SelectBuilder) ForShare() *SelectBuilder {
+	b.lockStrength = "SHARE"
+	return b
+}
+
+// Of restricts the locking clause to given tables: "FOR UPDATE OF t1, t2".
+func (b *SelectBuilder) Of(tables ...string) *SelectBuilder {
+	b.lockOf = append(b.lockOf, tables...)
+	return b
+}
+
+// SkipLocked adds SKIP LOCKED option to the locking clause.
+func (b *SelectBuilder) SkipLocked() *SelectBuilder {
+	b.lockWait = "SKIP LOCKED"
+	return b
+}
+
+// NoWait adds NOWAIT option to the locking clause.
+func (b *SelectBuilder) NoWait() *SelectBuilder {
+	b.lockWait = "NOWAIT"
+	return b
+}
+
+// Explain builds "EXPLAIN ..." statement showing the plan of the query.
+// The result is an ExplainBuilder sharing dialect, placeholder format, runner,
+// post processors and the context set by WithContext of the query.
+func (b *SelectBuilder) Explain() *ExplainBuilder {
+	return NewExplainBuilder(b.StatementBuilderType, b)
+}
+
+// ExplainAnalyze is like Explain, but the query is actually run to report its
+// timings. For PostgreSQL dialect the plan is in JSON: "EXPLAIN (ANALYZE, FORMAT JSON) ...".
+func (b *SelectBuilder) ExplainAnalyze() *ExplainBuilder {
+	e := b.Explain().Analyze()
+	if b.dialect == PostgresDialect {
+		e.Format("JSON")
+	}
+	return e
+}
+
+// Union combines the query with another one using UNION.
+// The result is a UnionBuilder sharing dialect, placeholder format, runner,
+// post processors and the context set by WithContext of the query. Settings
+// of the other queries are ignored, except for their SQL and args.
+// Ex:
+//     Select("id").From("a").Union(Select("id").From("b")).OrderBy("id")
+func (b *SelectBuilder) Union(query Sqlizer) *UnionBuilder {
+	return NewUnionBuilder(b.StatementBuilderType, b).Union(query)
+}
+
+// UnionAll combines the query with another one using UNION ALL.
+func (b *SelectBuilder) UnionAll(query Sqlizer) *UnionBuilder {
+	return NewUnionBuilder(b.StatementBuilderType, b).UnionAll(query)
+}
+
+// Intersect combines the query with another one using INTERSECT.
+func (b *SelectBuilder) Intersect(query Sqlizer) *UnionBuilder {
+	return NewUnionBuilder(b.StatementBuilderType, b).Intersect(query)
+}
+
+// Except combines the query with another one using EXCEPT.
+func (b *SelectBuilder) Except(query Sqlizer) *UnionBuilder {
+	return NewUnionBuilder(b.StatementBuilderType, b).Except(query)
+}
+
+// Suffix adds an expression to the end of the query.
+// Sqlizer args are embedded in place of their placeholders.
 func (b *SelectBuilder) Suffix(sql string, args ...interface{}) *SelectBuilder {
 	b.suffixes = append(b.suffixes, Expr(sql, args...))
 
 	return b
 }
+
+// GetColumns returns SQL of the result columns of the query, e.g. "a",
+// "COUNT(*) AS n" or "(SELECT ...) AS sub", with placeholders left as is.
+//
+// The result is a copy, changing it does not affect the query. Columns which
+// cannot be built are returned as empty strings, ToSql reports the error.
+func (b *SelectBuilder) GetColumns() []string {
+	return partsSql(b.columns, b.exprDialect())
+}
+
+// GetFrom returns SQL of the parts of FROM clause of the query, e.g. table
+// names or "(SELECT ...) AS t", with placeholders left as is.
+//
+// The result is a copy, changing it does not affect the query. Parts which
+// cannot be built are returned as empty strings, ToSql reports the error.
+func (b *SelectBuilder) GetFrom() []string {
+	return partsSql(b.fromParts, b.exprDialect())
+}
+
+// GetWhereArgs returns args bound to the WHERE clause of the query, in order.
+//
+// The result is a copy, changing it does not affect the query. Parts which
+// cannot be built are skipped, ToSql reports the error.
+func (b *SelectBuilder) GetWhereArgs() []interface{} {
+	var args []interface{}
+	for _, p := range b.whereParts {
+		_, partArgs, err := dialectToSql(p, b.exprDialect())
+		if err == nil {
+			args = append(args, partArgs...)
+		}
+	}
+	return args
+}
+
+func partsSql(parts []Sqlizer, d Dialect) []string {
+	result := make([]string, len(parts))
+	for i, p := range parts {
+		result[i], _, _ = dialectToSql(p, d)
+	}
+	return result
+}
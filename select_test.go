@@ -45,11 +45,11 @@ func TestSelectBuilderToSql(t *testing.T) {
 			"FROM e " +
 			"CROSS JOIN j1 JOIN j2 LEFT JOIN j3 RIGHT JOIN j4 " +
 			"WHERE f = ? AND g = ? AND h = ? AND i IN (?,?,?) AND (j = ? OR (k = ? AND true)) " +
-			"GROUP BY l HAVING m = n ORDER BY o ASC, p DESC LIMIT 12 OFFSET 13 " +
+			"GROUP BY l HAVING m = n ORDER BY o ASC, p DESC LIMIT ? OFFSET ? " +
 			"FETCH FIRST ? ROWS ONLY"
 	assert.Equal(t, expectedSql, sql)
 
-	expectedArgs := []interface{}{0, 1, 2, 3, 100, 101, 102, 103, 4, 5, 6, 7, 8, 9, 10, 11, 14}
+	expectedArgs := []interface{}{0, 1, 2, 3, 100, 101, 102, 103, 4, 5, 6, 7, 8, 9, 10, 11, uint64(12), uint64(13), 14}
 	assert.Equal(t, expectedArgs, args)
 }
 
@@ -89,11 +89,12 @@ func TestSelectBuilderZeroOffsetLimit(t *testing.T) {
 		Limit(0).
 		Offset(0)
 
-	sql, _, err := qb.ToSql()
+	sql, args, err := qb.ToSql()
 	assert.NoError(t, err)
 
-	expectedSql := "SELECT a FROM b LIMIT 0 OFFSET 0"
+	expectedSql := "SELECT a FROM b LIMIT ? OFFSET ?"
 	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{uint64(0), uint64(0)}, args)
 }
 
 
@@ -219,3 +220,443 @@ func TestSelectWithOptions(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "SELECT DISTINCT SQL_NO_CACHE * FROM foo", sql)
 }
+
+func TestSelectBuilderDistinctOn(t *testing.T) {
+	b := Select("a", "b").
+		DistinctOn("a", "lower(b)").
+		From("c").
+		Where("d = ?", 1).
+		OrderBy("a", "lower(b)", "e DESC").
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT ON (a, lower(b)) a, b FROM c WHERE d = $1 ORDER BY a, lower(b), e DESC", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	_, _, err = Select("a").Distinct().DistinctOn("a").From("c").ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderFullAndCrossJoin(t *testing.T) {
+	b := Select("*").
+		From("a").
+		Join("b ON a.id = b.a_id AND b.x = ?", 1).
+		FullJoin("c ON c.id = a.c_id AND c.y = ?", 2).
+		CrossJoin("d").
+		LeftJoin("e ON e.id = d.e_id AND e.z = ?", 3).
+		Where("a.w = ?", 4).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT * FROM a " +
+		"JOIN b ON a.id = b.a_id AND b.x = $1 " +
+		"FULL JOIN c ON c.id = a.c_id AND c.y = $2 " +
+		"CROSS JOIN d " +
+		"LEFT JOIN e ON e.id = d.e_id AND e.z = $3 " +
+		"WHERE a.w = $4"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 4}, args)
+}
+
+func TestSelectBuilderCrossJoinArgsErr(t *testing.T) {
+	_, _, err := Select("*").From("a").CrossJoin("b ON a.id = ?", 1).ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderJoinLateral(t *testing.T) {
+	lastOrder := Select("o.total").
+		From("orders o").
+		Where("o.user_id = u.id AND o.status = ?", "paid").
+		OrderBy("o.created_at DESC").
+		Limit(1)
+
+	b := Select("u.id", "lo.total").
+		FromSelect(Select("*").From("users").Where("active = ?", true), "u").
+		LeftJoinLateral(lastOrder, "lo").
+		JoinLateral(Select("count(*) AS n").From("visits v").Where("v.user_id = u.id"), "vc", "vc.n > ?", 3).
+		Where("u.created_at > ?", "2018-01-01").
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT u.id, lo.total " +
+		"FROM (SELECT * FROM users WHERE active = $1) AS u " +
+		"LEFT JOIN LATERAL (SELECT o.total FROM orders o WHERE o.user_id = u.id AND o.status = $2 " +
+		"ORDER BY o.created_at DESC LIMIT $3) lo ON true " +
+		"JOIN LATERAL (SELECT count(*) AS n FROM visits v WHERE v.user_id = u.id) vc ON vc.n > $4 " +
+		"WHERE u.created_at > $5"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{true, "paid", uint64(1), 3, "2018-01-01"}, args)
+}
+
+func TestSelectBuilderJoinLateralErr(t *testing.T) {
+	_, _, err := Select("*").From("a").JoinLateral(Select(), "b").ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select("*").From("a").JoinLateral(Select("1"), "b", 42).ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderLocking(t *testing.T) {
+	b := Select("*").
+		From("jobs j").
+		Join("queues q ON q.id = j.queue_id").
+		Where("j.status = ?", "new").
+		OrderBy("j.id").
+		Limit(10).
+		ForUpdate().
+		Of("j").
+		SkipLocked()
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM jobs j JOIN queues q ON q.id = j.queue_id WHERE j.status = ? "+
+		"ORDER BY j.id LIMIT ? FOR UPDATE OF j SKIP LOCKED", sql)
+	assert.Equal(t, []interface{}{"new", uint64(10)}, args)
+
+	sql, _, err = Select("*").From("a").ForShare().NoWait().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM a FOR SHARE NOWAIT", sql)
+
+	sql, _, err = Select("*").From("a").ForUpdate().Suffix("-- locked").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM a FOR UPDATE -- locked", sql)
+}
+
+func TestSelectBuilderLockingErr(t *testing.T) {
+	_, _, err := Select("*").From("a").SkipLocked().ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select("*").From("a").Of("a").ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderGroupByRollup(t *testing.T) {
+	b := Select("region", "product", "SUM(amount)").
+		From("sales").
+		GroupBy("year").
+		GroupByRollup("region", "product")
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT region, product, SUM(amount) FROM sales GROUP BY year, ROLLUP(region, product)", sql)
+
+	sql, _, err = b.Dialect(MySQLDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT region, product, SUM(amount) FROM sales GROUP BY year, region, product WITH ROLLUP", sql)
+
+	sql, _, err = b.Dialect(PostgresDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT region, product, SUM(amount) FROM sales GROUP BY year, ROLLUP(region, product)", sql)
+
+	_, _, err = b.Dialect(SQLiteDialect).ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderHavingSqlizer(t *testing.T) {
+	sql, args, err := Select("dept", "COUNT(*) AS cnt").
+		From("emp").
+		Where("active = ?", true).
+		GroupBy("dept").
+		Having(Gt{"COUNT(*)": 5}).
+		Having(Or{Eq{"dept": []string{"a", "b"}}, Expr("MAX(salary) > ?", 1000)}).
+		Suffix("-- ?", "suffix").
+		PlaceholderFormat(Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT dept, COUNT(*) AS cnt FROM emp WHERE active = $1 GROUP BY dept "+
+		"HAVING COUNT(*) > $2 AND (dept IN ($3,$4) OR MAX(salary) > $5) -- $6", sql)
+	assert.Equal(t, []interface{}{true, 5, "a", "b", 1000, "suffix"}, args)
+}
+
+func TestSelectBuilderLimitOffsetDialects(t *testing.T) {
+	b := Select("id").From("users").Where("age > ?", 18).OrderBy("id").Limit(10).Offset(20)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE age > ? ORDER BY id LIMIT ? OFFSET ?", sql)
+	assert.Equal(t, []interface{}{18, uint64(10), uint64(20)}, args)
+
+	sql, _, err = b.Dialect(PostgresDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE age > $1 ORDER BY id LIMIT $2 OFFSET $3", sql)
+
+	sql, args, err = b.Dialect(SQLServerDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE age > @p1 ORDER BY id OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY", sql)
+	assert.Equal(t, []interface{}{18, uint64(20), uint64(10)}, args)
+
+	sql, args, err = Select("id").From("users").OrderBy("id").Limit(10).Dialect(SQLServerDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY id OFFSET @p1 ROWS FETCH NEXT @p2 ROWS ONLY", sql)
+	assert.Equal(t, []interface{}{uint64(0), uint64(10)}, args)
+
+	sql, args, err = Select("id").From("users").OrderBy("id").Offset(5).Dialect(SQLServerDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY id OFFSET @p1 ROWS", sql)
+	assert.Equal(t, []interface{}{uint64(5)}, args)
+
+	_, _, err = Select("id").From("users").Limit(10).Dialect(SQLServerDialect).ToSql()
+	assert.EqualError(t, err, "LIMIT and OFFSET require ORDER BY clause for SQL Server dialect")
+
+	sql, _, err = b.Dialect(OracleDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE age > :1 ORDER BY id OFFSET :2 ROWS FETCH NEXT :3 ROWS ONLY", sql)
+
+	sql, args, err = Select("id").From("users").Limit(10).Dialect(OracleDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users FETCH FIRST :1 ROWS ONLY", sql)
+	assert.Equal(t, []interface{}{uint64(10)}, args)
+}
+
+func TestSelectBuilderRemoveLimitOffset(t *testing.T) {
+	b := Select("id").From("users").Where("age > ?", 18).Limit(10).Offset(20).PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE age > $1 LIMIT $2 OFFSET $3", sql)
+	assert.Equal(t, []interface{}{18, uint64(10), uint64(20)}, args)
+
+	sql, args, err = b.RemoveOffset().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE age > $1 LIMIT $2", sql)
+	assert.Equal(t, []interface{}{18, uint64(10)}, args)
+
+	sql, args, err = b.RemoveLimit().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE age > $1", sql)
+	assert.Equal(t, []interface{}{18}, args)
+}
+
+func TestSelectBuilderRemoveParts(t *testing.T) {
+	b := Select("id", "name").
+		From("users").
+		Join("orders o ON o.user_id = users.id").
+		Where("age > ?", 18).
+		GroupBy("id").
+		OrderBy("name").
+		Limit(10).
+		Offset(20).
+		PlaceholderFormat(Dollar)
+
+	count := b.Clone().RemoveColumns().Column("COUNT(*)").RemoveOrderBys().RemoveLimit().RemoveOffset()
+	sql, args, err := count.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM users JOIN orders o ON o.user_id = users.id WHERE age > $1 GROUP BY id", sql)
+	assert.Equal(t, []interface{}{18}, args)
+
+	sql, args, err = b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name FROM users JOIN orders o ON o.user_id = users.id WHERE age > $1 "+
+		"GROUP BY id ORDER BY name LIMIT $2 OFFSET $3", sql)
+	assert.Equal(t, []interface{}{18, uint64(10), uint64(20)}, args)
+
+	sql, args, err = count.RemoveWhere().Where("name = ?", "moe").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM users JOIN orders o ON o.user_id = users.id WHERE name = $1 GROUP BY id", sql)
+	assert.Equal(t, []interface{}{"moe"}, args)
+
+	_, _, err = count.RemoveColumns().ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderCloneIndexHints(t *testing.T) {
+	b := Select("*").From("t").UseIndex("a").Dialect(MySQLDialect)
+	c := b.Clone().UseIndex("b")
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t USE INDEX (a)", sql)
+
+	sql, _, err = c.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t USE INDEX (a) USE INDEX (b)", sql)
+}
+
+func TestSelectBuilderClone(t *testing.T) {
+	b := Select("a").From("t").Where("x = ?", 1).OrderBy("a")
+	c := b.Clone().Column("b").Where("y = ?", 2).OrderBy("b")
+	b.Where("z = ?", 3)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE x = ? AND z = ? ORDER BY a", sql)
+	assert.Equal(t, []interface{}{1, 3}, args)
+
+	sql, args, err = c.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a, b FROM t WHERE x = ? AND y = ? ORDER BY a, b", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestSelectBuilderClauseOrder(t *testing.T) {
+	sql, args, err := Select().
+		Suffix("/* ? */", "s").
+		SkipLocked().
+		Offset(20).
+		Limit(10).
+		OrderBy("a").
+		Window("w", Expr("PARTITION BY b")).
+		Having("COUNT(*) > ?", 1).
+		ForUpdate().
+		Of("t").
+		GroupBy("a", "b").
+		Where("c = ?", 2).
+		Join("u ON u.id = t.u_id AND u.kind = ?", 3).
+		From("t").
+		Columns("a", "b").
+		Prefix("/* ? */", "p").
+		PlaceholderFormat(Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	expectedSql := "/* $1 */ SELECT a, b FROM t JOIN u ON u.id = t.u_id AND u.kind = $2 " +
+		"WHERE c = $3 GROUP BY a, b HAVING COUNT(*) > $4 WINDOW w AS (PARTITION BY b) " +
+		"ORDER BY a LIMIT $5 OFFSET $6 FOR UPDATE OF t SKIP LOCKED /* $7 */"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"p", 3, 2, 1, uint64(10), uint64(20), "s"}, args)
+}
+
+func TestSelectBuilderPrefixSuffixSqlizer(t *testing.T) {
+	cte := Select("id").From("accounts").Where("owner = ? AND active = ?", "moe", true)
+	sql, args, err := Select("*").
+		Prefix("WITH a AS (?)", cte).
+		From("a").
+		Where("id > ?", 1).
+		Suffix("?", Expr("FOR UPDATE OF a SKIP LOCKED LIMIT ?", 5)).
+		PlaceholderFormat(Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	expectedSql := "WITH a AS (SELECT id FROM accounts WHERE owner = $1 AND active = $2) " +
+		"SELECT * FROM a WHERE id > $3 FOR UPDATE OF a SKIP LOCKED LIMIT $4"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"moe", true, 1, 5}, args)
+}
+
+func TestSelectBuilderGetters(t *testing.T) {
+	b := Select("a", "b").
+		Column(Alias(Select("COUNT(*)").From("c").Where("c.a = a.id AND c.x = ?", 1), "n")).
+		From("a").
+		FromSelect(Select("id").From("d").Where("y = ?", 2), "d").
+		Where("a.id > ?", 3).
+		Where(Eq{"b": []int{4, 5}}).
+		Having("COUNT(*) > ?", 6)
+
+	assert.Equal(t, []string{"a", "b", "(SELECT COUNT(*) FROM c WHERE c.a = a.id AND c.x = ?) AS n"}, b.GetColumns())
+	assert.Equal(t, []string{"a", "(SELECT id FROM d WHERE y = ?) AS d"}, b.GetFrom())
+	assert.Equal(t, []interface{}{3, 4, 5}, b.GetWhereArgs())
+
+	from := b.GetFrom()
+	from[0] = "secrets"
+	args := b.GetWhereArgs()
+	args[0] = 0
+	assert.Equal(t, []string{"a", "(SELECT id FROM d WHERE y = ?) AS d"}, b.GetFrom())
+	assert.Equal(t, []interface{}{3, 4, 5}, b.GetWhereArgs())
+
+	assert.Empty(t, Select().GetColumns())
+	assert.Nil(t, Select("a").GetWhereArgs())
+}
+
+func TestSelectBuilderOrderByClause(t *testing.T) {
+	sql, args, err := Select("id").
+		From("users").
+		Where("active = ?", true).
+		GroupBy("id").
+		Having("COUNT(*) > ?", 1).
+		OrderBy("priority DESC").
+		OrderByClause("FIELD(id, ?, ?)", 10, 20).
+		OrderBy("name").
+		OrderByClause("CASE WHEN role = ? THEN 0 ELSE 1 END", "admin").
+		OrderByClause(Expr("location <-> ?", "point")).
+		Limit(5).
+		PlaceholderFormat(Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	expectedSql := "SELECT id FROM users WHERE active = $1 GROUP BY id HAVING COUNT(*) > $2 " +
+		"ORDER BY priority DESC, FIELD(id, $3, $4), name, CASE WHEN role = $5 THEN 0 ELSE 1 END, location <-> $6 LIMIT $7"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{true, 1, 10, 20, "admin", "point", uint64(5)}, args)
+}
+
+func TestSelectBuilderIf(t *testing.T) {
+	b := Select("id").From("users").Where("active = ?", true)
+	before := *b
+
+	b.WhereIf(false, "name = ?", "moe").OrderByIf(false, "name").ColumnIf(false, "email")
+	assert.Equal(t, before, *b)
+
+	sql, args, err := b.WhereIf(true, "name = ?", "moe").OrderByIf(true, "name").ColumnIf(true, "? AS n", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, ? AS n FROM users WHERE active = ? AND name = ? ORDER BY name", sql)
+	assert.Equal(t, []interface{}{1, true, "moe"}, args)
+}
+
+func TestSelectBuilderIndexHints(t *testing.T) {
+	sql, args, err := Select("*").
+		From("a").UseIndex("idx_created").
+		From("b").ForceIndex("idx_x", "idx_y").IgnoreIndex("PRIMARY").
+		Where("a.id = b.a_id AND a.created > ?", 1).
+		Dialect(MySQLDialect).
+		ToSql()
+
+	assert.NoError(t, err)
+	expectedSql := "SELECT * FROM a USE INDEX (idx_created), " +
+		"b FORCE INDEX (idx_x, idx_y) IGNORE INDEX (PRIMARY) " +
+		"WHERE a.id = b.a_id AND a.created > ?"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestSelectBuilderIndexHintsErrors(t *testing.T) {
+	_, _, err := Select("*").From("a").UseIndex("idx").Dialect(PostgresDialect).ToSql()
+	assert.EqualError(t, err, "index hint is not supported by PostgreSQL dialect")
+
+	_, _, err = Select("*").From("a").UseIndex("idx").ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select("*").UseIndex("idx").Dialect(MySQLDialect).ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderJoinOnSqlizer(t *testing.T) {
+	sql, args, err := Select("u.id", "o.id").
+		From("users u").
+		Join("(SELECT * FROM orders WHERE shop = ?) o ON o.user_id = u.id", 1).
+		LeftJoin("coupons c", And{Eq{"c.order_id": Expr("o.id")}, Gt{"c.value": 100}}).
+		RightJoin("shops s", Eq{"s.id": Expr("o.shop_id")}).
+		FullJoin("notes n", Expr("n.user_id = u.id AND n.kind = ?", "vip")).
+		Where("u.active = ?", true).
+		PlaceholderFormat(Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	expectedSql := "SELECT u.id, o.id FROM users u " +
+		"JOIN (SELECT * FROM orders WHERE shop = $1) o ON o.user_id = u.id " +
+		"LEFT JOIN coupons c ON (c.order_id = o.id AND c.value > $2) " +
+		"RIGHT JOIN shops s ON (s.id = o.shop_id) " +
+		"FULL JOIN notes n ON (n.user_id = u.id AND n.kind = $3) " +
+		"WHERE u.active = $4"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, 100, "vip", true}, args)
+}
+
+func TestSelectBuilderJoinOnOr(t *testing.T) {
+	sql, args, err := Select("*").
+		From("a").
+		Join("b", Or{Eq{"b.a_id": Expr("a.id")}, Eq{"b.owner": 1}}).
+		LeftJoin("c", Expr("c.a_id = a.id OR c.shared")).
+		Where("a.x = ?", 2).
+		ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM a JOIN b ON (b.a_id = a.id OR b.owner = ?) "+
+		"LEFT JOIN c ON (c.a_id = a.id OR c.shared) WHERE a.x = ?", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
@@ -86,6 +86,26 @@ var ErrRunnerNotQueryRunner = fmt.Errorf("cannot QueryRow; Runner is not a Query
 // ErrRunnerNotQueryRunnerContext is returned by QueryRowContext if the RunWith value doesn't implement QueryRowerContext.
 var ErrRunnerNotQueryRunnerContext = fmt.Errorf("cannot QueryRow; Runner is not a QueryRowerContext")
 
+// ToSql returns SQL and args built by s, so any Sqlizer, e.g. Expr or And,
+// could be built the same way as statement builders.
+func ToSql(s Sqlizer) (string, []interface{}, error) {
+	return s.ToSql()
+}
+
+// MustSql returns SQL and args built by s and panics if s fails to build.
+// s is any Sqlizer, e.g. MustSql(Expr("a = ?", 1)); builders have MustSql
+// methods calling it.
+//
+// It is meant for tests asserting built statements only; use ToSql and handle
+// the error in other code.
+func MustSql(s Sqlizer) (string, []interface{}) {
+	sql, args, err := ToSql(s)
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
 // ExecWith Execs the SQL returned by s with db.
 func ExecWith(db Execer, s Sqlizer) (res sql.Result, err error) {
 	query, args, err := s.ToSql()
@@ -125,13 +145,62 @@ func QueryWithContext(ctx context.Context, db QueryerContext, s Sqlizer) (rows *
 // QueryRowWith QueryRows the SQL returned by s with db.
 func QueryRowWith(db QueryRower, s Sqlizer) RowScanner {
 	query, args, err := s.ToSql()
-	return &Row{RowScanner: db.QueryRow(query, args...), err: err}
+	if err != nil {
+		return &Row{err: err}
+	}
+	return &Row{RowScanner: db.QueryRow(query, args...)}
 }
 
 // QueryRowWithContext QueryRows the SQL returned by s with db.
 func QueryRowWithContext(ctx context.Context, db QueryRowerContext, s Sqlizer) RowScanner {
 	query, args, err := s.ToSql()
-	return &Row{RowScanner: db.QueryRowContext(ctx, query, args...), err: err}
+	if err != nil {
+		return &Row{err: err}
+	}
+	return &Row{RowScanner: db.QueryRowContext(ctx, query, args...)}
+}
+
+// ExecBatchContext Execs the SQL returned by each of stmts in order in a single
+// transaction begun with db. The transaction is committed if all of them
+// succeed; otherwise it is rolled back and the error of the first failed
+// statement is returned along with its index in stmts. It is rolled back as
+// well if a statement panics while built.
+//
+// The transaction is begun with BeginTx(ctx, nil) if db implements it, or with
+// Begin otherwise.
+func ExecBatchContext(ctx context.Context, db DBProxyBeginner, stmts ...Sqlizer) error {
+	var tx *sql.Tx
+	var err error
+	if b, ok := db.(txBeginnerContext); ok {
+		tx, err = b.BeginTx(ctx, nil)
+	} else {
+		tx, err = db.Begin()
+	}
+	if err != nil {
+		return err
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	for i, s := range stmts {
+		if _, err = ExecWithContext(ctx, tx, s); err != nil {
+			return fmt.Errorf("batch statement %d: %w", i, err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+type txBeginnerContext interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
 }
 
 // DBRunner wraps sql.DB to implement Runner.
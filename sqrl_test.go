@@ -3,6 +3,11 @@ package sqrl
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -23,6 +28,8 @@ type DBStub struct {
 
 	LastQueryRowSql  string
 	LastQueryRowArgs []interface{}
+
+	LastCtx context.Context
 }
 
 func (s *DBStub) Prepare(query string) (*sql.Stmt, error) {
@@ -44,6 +51,7 @@ func (s *DBStub) Exec(query string, args ...interface{}) (sql.Result, error) {
 }
 
 func (s *DBStub) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	s.LastCtx = ctx
 	s.LastExecSql = query
 	s.LastExecArgs = args
 	return s.res, s.err
@@ -56,6 +64,7 @@ func (s *DBStub) Query(query string, args ...interface{}) (*sql.Rows, error) {
 }
 
 func (s *DBStub) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	s.LastCtx = ctx
 	s.LastQuerySql = query
 	s.LastQueryArgs = args
 	return nil, nil
@@ -68,11 +77,165 @@ func (s *DBStub) QueryRow(query string, args ...interface{}) RowScanner {
 }
 
 func (s *DBStub) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	s.LastCtx = ctx
 	s.LastQueryRowSql = query
 	s.LastQueryRowArgs = args
 	return &Row{RowScanner: &RowStub{}}
 }
 
+// fakeDriver is a database/sql driver counting prepared and closed statements
+type fakeDriver struct {
+	mu       sync.Mutex
+	prepared map[string]int
+	closed   map[string]int
+	failures map[string]error
+	results  map[string]fakeRows
+
+	commits   int
+	rollbacks int
+}
+
+// setRows makes queries return rows of values for columns
+func (d *fakeDriver) setRows(query string, columns []string, values ...[]driver.Value) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.results[query] = fakeRows{columns: columns, values: values}
+}
+
+// fail makes the next execution of query fail with err
+func (d *fakeDriver) fail(query string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failures[query] = err
+}
+
+func (d *fakeDriver) failure(query string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	err := d.failures[query]
+	delete(d.failures, query)
+	return err
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+func (d *fakeDriver) txCounts() (commits, rollbacks int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.commits, d.rollbacks
+}
+
+func (d *fakeDriver) counts(query string) (prepared, closed int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.prepared[query], d.closed[query]
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.driver.prepared[query]++
+	return &fakeStmt{driver: c.driver, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return &fakeTx{driver: c.driver}, nil }
+
+type fakeTx struct {
+	driver *fakeDriver
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.driver.mu.Lock()
+	defer tx.driver.mu.Unlock()
+	tx.driver.commits++
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.driver.mu.Lock()
+	defer tx.driver.mu.Unlock()
+	tx.driver.rollbacks++
+	return nil
+}
+
+type fakeStmt struct {
+	driver *fakeDriver
+	query  string
+}
+
+func (s *fakeStmt) Close() error {
+	s.driver.mu.Lock()
+	defer s.driver.mu.Unlock()
+	s.driver.closed[s.query]++
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if err := s.driver.failure(s.query); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if err := s.driver.failure(s.query); err != nil {
+		return nil, err
+	}
+	s.driver.mu.Lock()
+	defer s.driver.mu.Unlock()
+	rows := s.driver.results[s.query]
+	return &rows, nil
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+var fakeDrivers = struct {
+	sync.Mutex
+	n int
+}{}
+
+// openFakeDB registers a new fakeDriver and opens a database using it
+func openFakeDB(t *testing.T) (*sql.DB, *fakeDriver) {
+	d := &fakeDriver{prepared: map[string]int{}, closed: map[string]int{}, failures: map[string]error{}, results: map[string]fakeRows{}}
+
+	fakeDrivers.Lock()
+	fakeDrivers.n++
+	name := fmt.Sprintf("sqrl-fake-%d", fakeDrivers.n)
+	fakeDrivers.Unlock()
+	sql.Register(name, d)
+
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, d
+}
+
 type resultStub struct {
 	rowsAffected int64
 	lastInsertId int64
@@ -108,6 +271,49 @@ func TestQueryRowWith(t *testing.T) {
 	assert.Equal(t, sqlStr, db.LastQueryRowSql)
 }
 
+func TestMustSql(t *testing.T) {
+	sql, args := MustSql(Expr("a = ?", 1))
+	assert.Equal(t, "a = ?", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args = Select("*").From("t").Where("a = ?", 1).PlaceholderFormat(Dollar).MustSql()
+	assert.Equal(t, "SELECT * FROM t WHERE a = $1", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, _ = Insert("t").Values(1).MustSql()
+	assert.Equal(t, "INSERT INTO t VALUES (?)", sql)
+
+	sql, _ = Update("t").Set("a", 1).MustSql()
+	assert.Equal(t, "UPDATE t SET a = ?", sql)
+
+	sql, _ = Delete("t").MustSql()
+	assert.Equal(t, "DELETE FROM t", sql)
+
+	sql, _ = Values(1, 2).MustSql()
+	assert.Equal(t, "VALUES (?,?)", sql)
+
+	sql, args = MustSql(And{Expr("a = ?", 1), Eq{"b": []int{2, 3}}})
+	assert.Equal(t, "(a = ? AND b IN (?,?))", sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+
+	assert.Panics(t, func() { MustSql(errSqlizer{}) })
+	assert.Panics(t, func() { Select().MustSql() })
+	assert.Panics(t, func() { Insert("t").MustSql() })
+	assert.Panics(t, func() { Update("t").MustSql() })
+	assert.Panics(t, func() { Delete("").MustSql() })
+	assert.Panics(t, func() { Values().MustSql() })
+}
+
+func TestToSql(t *testing.T) {
+	sql, args, err := ToSql(Expr("a = ?", 1))
+	assert.NoError(t, err)
+	assert.Equal(t, "a = ?", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	_, _, err = ToSql(errSqlizer{})
+	assert.EqualError(t, err, "broken")
+}
+
 func TestWithToSqlErr(t *testing.T) {
 	db := &DBStub{}
 	sqlizer := Select()
@@ -121,3 +327,67 @@ func TestWithToSqlErr(t *testing.T) {
 	err = QueryRowWith(db, sqlizer).Scan()
 	assert.Error(t, err)
 }
+
+func TestExecBatchContext(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	err := ExecBatchContext(context.Background(), NewStmtCacheProxy(db),
+		Truncate("users"),
+		Insert("users").Columns("name").Values("moe"),
+	)
+	assert.NoError(t, err)
+
+	commits, rollbacks := d.txCounts()
+	assert.Equal(t, 1, commits)
+	assert.Equal(t, 0, rollbacks)
+	prepared, _ := d.counts("INSERT INTO users (name) VALUES (?)")
+	assert.Equal(t, 1, prepared)
+}
+
+func TestExecBatchContextRollback(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	failure := fmt.Errorf("duplicate key")
+	d.fail("INSERT INTO users (name) VALUES (?)", failure)
+
+	err := ExecBatchContext(context.Background(), NewStmtCacheProxy(db),
+		Truncate("users"),
+		Insert("users").Columns("name").Values("moe"),
+		Update("users").Set("name", "larry"),
+	)
+	assert.EqualError(t, err, "batch statement 1: duplicate key")
+	assert.True(t, errors.Is(err, failure))
+
+	commits, rollbacks := d.txCounts()
+	assert.Equal(t, 0, commits)
+	assert.Equal(t, 1, rollbacks)
+	prepared, _ := d.counts("UPDATE users SET name = ?")
+	assert.Equal(t, 0, prepared)
+
+	err = ExecBatchContext(context.Background(), NewStmtCacheProxy(db), Select())
+	assert.Error(t, err)
+	_, rollbacks = d.txCounts()
+	assert.Equal(t, 2, rollbacks)
+}
+
+// panicSqlizer panics when built
+type panicSqlizer struct{}
+
+func (panicSqlizer) ToSql() (string, []interface{}, error) {
+	panic("broken sqlizer")
+}
+
+func TestExecBatchContextPanic(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	assert.PanicsWithValue(t, "broken sqlizer", func() {
+		ExecBatchContext(context.Background(), NewStmtCacheProxy(db), Truncate("users"), panicSqlizer{})
+	})
+
+	commits, rollbacks := d.txCounts()
+	assert.Equal(t, 0, commits)
+	assert.Equal(t, 1, rollbacks)
+}
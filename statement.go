@@ -1,9 +1,83 @@
 package sqrl
 
+import "context"
+
 // StatementBuilderType is the type of StatementBuilder.
 type StatementBuilderType struct {
 	placeholderFormat PlaceholderFormat
 	runWith           BaseRunner
+	dialect           Dialect
+	postProcessors    []PostProcessor
+	ctx               context.Context
+}
+
+// runContext returns the context set by WithContext of a builder, if any.
+func (b StatementBuilderType) runContext() context.Context {
+	if b.ctx == nil {
+		return context.Background()
+	}
+	return b.ctx
+}
+
+// PostProcessor transforms SQL and args built by ToSql of a statement,
+// e.g. to add a comment or collect metrics.
+//
+// See StatementBuilderType.PostProcess.
+type PostProcessor func(sql string, args []interface{}) (string, []interface{}, error)
+
+// formatSql replaces placeholders in SQL generated by child builders and
+// runs post processors.
+func (b StatementBuilderType) formatSql(sql string, args []interface{}) (string, []interface{}, error) {
+	f := b.placeholderFormat
+	if f == nil {
+		f = Question
+	}
+
+	var err error
+	if af, ok := f.(ArgsPlaceholderFormat); ok {
+		sql, args, err = af.ReplacePlaceholdersArgs(sql, args)
+	} else {
+		sql, err = f.ReplacePlaceholders(sql)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return b.postProcess(sql, args)
+}
+
+// postProcess runs post processors of the statement.
+func (b StatementBuilderType) postProcess(sql string, args []interface{}) (string, []interface{}, error) {
+	var err error
+	for _, p := range b.postProcessors {
+		sql, args, err = p(sql, args)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return sql, args, nil
+}
+
+// cloneSqlizers returns a copy of parts, so appending to either of them does
+// not change the other one.
+func cloneSqlizers(parts []Sqlizer) []Sqlizer {
+	return append([]Sqlizer(nil), parts...)
+}
+
+// cloneStrings returns a copy of s, see cloneSqlizers.
+func cloneStrings(s []string) []string {
+	return append([]string(nil), s...)
+}
+
+// exprDialect returns dialect expressions of the statement are rendered for.
+// Without dialect Dollar placeholders mean PostgreSQL, so e.g. ILike and Cast
+// get its syntax with PlaceholderFormat(Dollar) alone.
+func (b StatementBuilderType) exprDialect() Dialect {
+	if b.dialect == NoDialect {
+		if _, isDollar := b.placeholderFormat.(dollarFormat); isDollar {
+			return PostgresDialect
+		}
+	}
+	return b.dialect
 }
 
 // Select returns a SelectBuilder for this StatementBuilder.
@@ -26,12 +100,57 @@ func (b StatementBuilderType) Delete(what ...string) *DeleteBuilder {
 	return NewDeleteBuilder(b).What(what...)
 }
 
+// Truncate returns a TruncateBuilder for this StatementBuilder.
+func (b StatementBuilderType) Truncate(tables ...string) *TruncateBuilder {
+	return NewTruncateBuilder(b).Tables(tables...)
+}
+
+// Values returns a ValuesBuilder for this StatementBuilder.
+func (b StatementBuilderType) Values(values ...interface{}) *ValuesBuilder {
+	vb := NewValuesBuilder(b)
+	if len(values) > 0 {
+		vb.Values(values...)
+	}
+	return vb
+}
+
 // PlaceholderFormat sets the PlaceholderFormat field for any child builders.
 func (b StatementBuilderType) PlaceholderFormat(f PlaceholderFormat) StatementBuilderType {
 	b.placeholderFormat = f
 	return b
 }
 
+// Dialect sets the Dialect and its PlaceholderFormat for any child builders.
+func (b StatementBuilderType) Dialect(d Dialect) StatementBuilderType {
+	b.dialect = d
+	b.placeholderFormat = d.PlaceholderFormat()
+	return b
+}
+
+// PostProcess adds a PostProcessor for any child builders. It runs on the result
+// of their ToSql, after placeholders are replaced; an error it returns is
+// returned by ToSql. Post processors run in the order they were added.
+//
+// Statements nested into other ones are processed only as a part of the outer one.
+// Ex:
+//     sb := StatementBuilder.PostProcess(func(sql string, args []interface{}) (string, []interface{}, error) {
+//         return "/* app:orders */ " + sql, args, nil
+//     })
+func (b StatementBuilderType) PostProcess(p PostProcessor) StatementBuilderType {
+	b.postProcessors = append(b.postProcessors[:len(b.postProcessors):len(b.postProcessors)], p)
+	return b
+}
+
+// Sanitize adds sanitizers normalizing SQL of any child builders, e.g.
+// TrimSemicolon or CollapseWhitespace. They run as a PostProcessor, after
+// placeholders are replaced.
+func (b StatementBuilderType) Sanitize(sanitizers ...Sanitizer) StatementBuilderType {
+	s := Sanitizers(sanitizers...)
+	return b.PostProcess(func(sql string, args []interface{}) (string, []interface{}, error) {
+		return s(sql), args, nil
+	})
+}
+
 // RunWith sets the RunWith field for any child builders.
 func (b StatementBuilderType) RunWith(runner BaseRunner) StatementBuilderType {
 	b.runWith = wrapRunner(runner)
@@ -70,6 +189,20 @@ func Delete(what ...string) *DeleteBuilder {
 	return StatementBuilder.Delete(what...)
 }
 
+// Truncate returns a new TruncateBuilder for given table names.
+//
+// See TruncateBuilder.Tables.
+func Truncate(tables ...string) *TruncateBuilder {
+	return StatementBuilder.Truncate(tables...)
+}
+
+// Values returns a new ValuesBuilder, optionally adding the first row.
+//
+// See ValuesBuilder.Values.
+func Values(values ...interface{}) *ValuesBuilder {
+	return StatementBuilder.Values(values...)
+}
+
 // Case returns a new CaseBuilder
 // "what" represents case value
 func Case(what ...interface{}) *CaseBuilder {
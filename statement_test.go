@@ -1,7 +1,9 @@
 package sqrl
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -43,3 +45,102 @@ func TestRunWithTx(t *testing.T) {
 		Delete("t").RunWith(tx)
 	}, "RunWith(*sql.Tx) should not panic")
 }
+
+func TestStatementBuilderPostProcess(t *testing.T) {
+	tag := func(sql string, args []interface{}) (string, []interface{}, error) {
+		return "/* app:orders */ " + sql, args, nil
+	}
+	var count int
+	counter := func(sql string, args []interface{}) (string, []interface{}, error) {
+		count++
+		return sql, args, nil
+	}
+
+	sb := StatementBuilder.PlaceholderFormat(Dollar).PostProcess(tag)
+	counted := sb.PostProcess(counter)
+
+	sql, args, err := counted.Select("*").From("orders").Where(Eq{"id": sb.Select("order_id").From("items").Where("x = ?", 1)}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "/* app:orders */ SELECT * FROM orders WHERE id IN (SELECT order_id FROM items WHERE x = $1)", sql)
+	assert.Equal(t, []interface{}{1}, args)
+	assert.Equal(t, 1, count)
+
+	sql, _, err = sb.Update("orders").Set("a", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "/* app:orders */ UPDATE orders SET a = $1", sql)
+	assert.Equal(t, 1, count)
+
+	sql, _, err = counted.Insert("orders").Values(1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "/* app:orders */ INSERT INTO orders VALUES ($1)", sql)
+	assert.Equal(t, 2, count)
+
+	sql, _, err = counted.Delete("orders").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "/* app:orders */ DELETE FROM orders", sql)
+	assert.Equal(t, 3, count)
+
+	sql, _, err = Select("1").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT 1", sql)
+}
+
+func TestStatementBuilderPostProcessErr(t *testing.T) {
+	sb := StatementBuilder.PostProcess(func(sql string, args []interface{}) (string, []interface{}, error) {
+		return "", nil, errors.New("rejected")
+	})
+
+	_, _, err := sb.Select("1").ToSql()
+	assert.EqualError(t, err, "rejected")
+
+	db := &DBStub{}
+	_, err = sb.RunWith(db).Select("1").Exec()
+	assert.EqualError(t, err, "rejected")
+	assert.Empty(t, db.LastExecSql)
+}
+
+type ctxKey struct{}
+
+func TestBuildersWithContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	db := &DBStub{}
+
+	Select("a").From("t").RunWith(db).WithContext(ctx).Query()
+	assert.Equal(t, ctx, db.LastCtx)
+
+	db.LastCtx = nil
+	Select("a").From("t").RunWith(db).WithContext(ctx).Scan()
+	assert.Equal(t, ctx, db.LastCtx)
+
+	db.LastCtx = nil
+	Insert("t").Values(1).RunWith(db).WithContext(ctx).Exec()
+	assert.Equal(t, ctx, db.LastCtx)
+	assert.Equal(t, "INSERT INTO t VALUES (?)", db.LastExecSql)
+
+	db.LastCtx = nil
+	Update("t").Set("a", 1).RunWith(db).WithContext(ctx).Exec()
+	assert.Equal(t, ctx, db.LastCtx)
+
+	db.LastCtx = nil
+	Delete("t").RunWith(db).WithContext(ctx).Exec()
+	assert.Equal(t, ctx, db.LastCtx)
+
+	db.LastCtx = nil
+	Select("a").From("t").Union(Select("a").From("u")).RunWith(db).WithContext(ctx).QueryRow()
+	assert.Equal(t, ctx, db.LastCtx)
+
+	db.LastCtx = nil
+	Select("a").From("t").WithContext(ctx).Explain().RunWith(db).Query()
+	assert.Equal(t, ctx, db.LastCtx)
+}
+
+func TestBuildersWithoutContext(t *testing.T) {
+	db := &DBStub{}
+	Update("t").Set("a", 1).RunWith(db).Exec()
+	assert.Equal(t, context.Background(), db.LastCtx)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	Update("t").Set("a", 1).RunWith(db).WithContext(ctx).ExecContext(context.TODO())
+	assert.Equal(t, context.TODO(), db.LastCtx, "explicit context wins")
+}
@@ -3,7 +3,10 @@ package sqrl
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"strings"
 	"sync"
+	"time"
 )
 
 // Preparer is the interface that wraps the Prepare method.
@@ -26,55 +29,360 @@ type DBProxy interface {
 	QueryRowerContext
 }
 
+// defaultStmtMaxAge is the time statements are kept in cache of
+// NewStmtCacherContext since their last use, unless WithMaxAge is given
+const defaultStmtMaxAge = 4 * time.Hour
+
+// ErrStmtCacherClosed is returned when statement cache is used after its
+// context was canceled.
+var ErrStmtCacherClosed = errors.New("statement cache is closed")
+
+type cachedStmt struct {
+	stmt    *sql.Stmt
+	lastUse time.Time
+
+	// users is the number of queries running with stmt, it is closed when
+	// evicted only after all of them are done
+	users   int
+	evicted bool
+}
+
 type stmtCacher struct {
-	prep  Preparer
-	cache map[string]*sql.Stmt
-	mu    sync.Mutex
+	prep   Preparer
+	cache  map[string]*cachedStmt
+	mu     sync.Mutex
+	closed bool
+
+	maxAge        time.Duration
+	maxStatements int
+	retryable     func(err error) bool
+	done          chan struct{}
+
+	stats StmtCacheStats
+}
+
+// StmtCacheStats are counters of a statement cache, useful to tune its options.
+type StmtCacheStats struct {
+	// Hits is the number of queries run with a cached statement.
+	Hits uint64
+	// Misses is the number of queries which statements had to be prepared.
+	Misses uint64
+	// Evictions is the number of statements closed because they expired, were
+	// least recently used or stale.
+	Evictions uint64
+	// Size is the number of statements in cache.
+	Size int
+}
+
+// StmtCacheStatsReporter is implemented by DBProxy returned by NewStmtCacher,
+// NewStmtCacherContext and NewStmtCacheProxy.
+// Ex:
+//     if r, ok := db.(sqrl.StmtCacheStatsReporter); ok {
+//         log.Printf("statement cache: %+v", r.Stats())
+//     }
+type StmtCacheStatsReporter interface {
+	Stats() StmtCacheStats
+}
+
+// StmtCacherOption configures a statement cache, see NewStmtCacherContext.
+type StmtCacherOption func(*stmtCacher)
+
+// WithMaxAge sets the time a statement is kept in cache since its last use.
+// Expired statements are closed and prepared again on the next use.
+// Zero age keeps statements until the cache is closed.
+func WithMaxAge(age time.Duration) StmtCacherOption {
+	return func(sc *stmtCacher) {
+		sc.maxAge = age
+	}
+}
+
+// WithRetryableErrors sets a matcher of errors meaning the cached statement is
+// stale, e.g. after a schema change. Statements failing with such errors are
+// closed, prepared again and the query is retried once.
+//
+// By default PostgreSQL and MySQL errors about invalidated statements are matched,
+// see IsStaleStmtError.
+func WithRetryableErrors(retryable func(err error) bool) StmtCacherOption {
+	return func(sc *stmtCacher) {
+		sc.retryable = retryable
+	}
+}
+
+// IsStaleStmtError tells whether err reports a prepared statement that has to
+// be prepared again: "cached plan must not change result type" or a missing
+// prepared statement for PostgreSQL, "Prepared statement needs to be re-prepared"
+// for MySQL.
+func IsStaleStmtError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "cached plan must not change result type") ||
+		(strings.Contains(msg, "prepared statement") && strings.Contains(msg, "does not exist")) ||
+		strings.Contains(msg, "Prepared statement needs to be re-prepared")
+}
+
+// WithMaxStatements limits the number of cached statements. When the limit is
+// exceeded, the least recently used statement is removed from cache and closed
+// once queries running with it are done. A statement returned by Prepare is
+// closed as well, so it is valid only until it is evicted. Zero means no limit.
+func WithMaxStatements(n int) StmtCacherOption {
+	return func(sc *stmtCacher) {
+		sc.maxStatements = n
+	}
+}
+
+func newStmtCacher(prep Preparer, maxAge time.Duration, opts []StmtCacherOption) *stmtCacher {
+	sc := &stmtCacher{
+		prep:      prep,
+		cache:     make(map[string]*cachedStmt),
+		maxAge:    maxAge,
+		retryable: IsStaleStmtError,
+	}
+	for _, opt := range opts {
+		opt(sc)
+	}
+	return sc
 }
 
 // NewStmtCacher returns a DBProxy wrapping prep that caches Prepared Stmts.
 //
-// Stmts are cached based on the string value of their queries.
-func NewStmtCacher(prep Preparer) DBProxy {
-	return &stmtCacher{prep: prep, cache: make(map[string]*sql.Stmt)}
+// Stmts are cached based on the string value of their queries. Unless options
+// say otherwise, they're kept forever; use NewStmtCacherContext to get rid
+// of unused ones in background.
+func NewStmtCacher(prep Preparer, opts ...StmtCacherOption) DBProxy {
+	return newStmtCacher(prep, 0, opts)
+}
+
+// NewStmtCacherContext returns a DBProxy wrapping prep that caches Prepared Stmts
+// and closes them in background when they are not used for max age, 4 hours by
+// default.
+//
+// When ctx is canceled all cached Stmts are closed, the background cleanup stops
+// and the cache returns ErrStmtCacherClosed from then on.
+func NewStmtCacherContext(ctx context.Context, prep Preparer, opts ...StmtCacherOption) DBProxy {
+	sc := newStmtCacher(prep, defaultStmtMaxAge, opts)
+	sc.done = make(chan struct{})
+	go sc.cleanup(ctx)
+	return sc
+}
+
+// cleanup closes expired statements until ctx is done, then closes the cache
+func (sc *stmtCacher) cleanup(ctx context.Context) {
+	defer close(sc.done)
+
+	var tick <-chan time.Time
+	if sc.maxAge > 0 {
+		ticker := time.NewTicker(sc.maxAge)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			sc.close()
+			return
+		case now := <-tick:
+			sc.closeExpired(now)
+		}
+	}
+}
+
+func (sc *stmtCacher) closeExpired(now time.Time) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for query, cached := range sc.cache {
+		if sc.expired(cached, now) {
+			sc.discard(query, cached)
+		}
+	}
+}
+
+func (sc *stmtCacher) close() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for query, cached := range sc.cache {
+		closeStmt(cached.stmt)
+		delete(sc.cache, query)
+	}
+	sc.closed = true
+}
+
+// discard removes cached statement of query from cache. It is closed right away
+// unless queries are running with it.
+func (sc *stmtCacher) discard(query string, cached *cachedStmt) {
+	delete(sc.cache, query)
+	sc.stats.Evictions++
+	cached.evicted = true
+	if cached.users == 0 {
+		closeStmt(cached.stmt)
+	}
 }
 
+func (sc *stmtCacher) expired(cached *cachedStmt, now time.Time) bool {
+	return sc.maxAge > 0 && now.Sub(cached.lastUse) >= sc.maxAge
+}
+
+// closeStmt closes stmt, which could be nil if Preparer returned no error without a statement
+func closeStmt(stmt *sql.Stmt) {
+	if stmt != nil {
+		stmt.Close()
+	}
+}
+
+// PrepareContext returns the cached statement of query, preparing it if needed.
+//
+// The statement is owned by the cache and must not be closed. It is valid only
+// until it is removed from cache: when it expires, is evicted by the limit of
+// WithMaxStatements or the cache is closed. Use Exec, Query and QueryRow of
+// the cache to run queries safely in the meantime.
 func (sc *stmtCacher) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
 	sc.mu.Lock()
 	defer sc.mu.Unlock()
-	stmt, ok := sc.cache[query]
+	cached, err := sc.prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return cached.stmt, nil
+}
+
+// prepare returns the cached statement of query, preparing it if needed.
+// sc.mu must be held.
+func (sc *stmtCacher) prepare(ctx context.Context, query string) (*cachedStmt, error) {
+	if sc.closed {
+		return nil, ErrStmtCacherClosed
+	}
+
+	now := time.Now()
+	cached, ok := sc.cache[query]
+	if ok && !sc.expired(cached, now) {
+		cached.lastUse = now
+		sc.stats.Hits++
+		return cached, nil
+	}
 	if ok {
-		return stmt, nil
+		sc.discard(query, cached)
 	}
+	sc.stats.Misses++
+
 	stmt, err := sc.prep.PrepareContext(ctx, query)
-	if err == nil {
-		sc.cache[query] = stmt
+	if err != nil {
+		return nil, err
+	}
+	cached = &cachedStmt{stmt: stmt, lastUse: now}
+	sc.cache[query] = cached
+	if sc.maxStatements > 0 && len(sc.cache) > sc.maxStatements {
+		sc.evictLeastRecentlyUsed(query)
 	}
-	return stmt, err
+	return cached, nil
 }
 
-func (sc *stmtCacher) ExecContext(ctx context.Context, query string, args ...interface{}) (res sql.Result, err error) {
-	stmt, err := sc.PrepareContext(ctx, query)
+// acquire returns the cached statement of query marked as used by a running
+// query, so it is not closed until release.
+func (sc *stmtCacher) acquire(ctx context.Context, query string) (*cachedStmt, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	cached, err := sc.prepare(ctx, query)
 	if err != nil {
-		return
+		return nil, err
+	}
+	cached.users++
+	return cached, nil
+}
+
+// release marks the end of a query running with cached statement and closes it
+// if it was evicted meanwhile.
+func (sc *stmtCacher) release(cached *cachedStmt) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	cached.users--
+	if cached.evicted && cached.users == 0 {
+		closeStmt(cached.stmt)
 	}
-	return stmt.ExecContext(ctx, args...)
+}
+
+// evictLeastRecentlyUsed discards the statement used longest ago, except the one
+// of keep query
+func (sc *stmtCacher) evictLeastRecentlyUsed(keep string) {
+	var oldestQuery string
+	var oldest *cachedStmt
+	for query, cached := range sc.cache {
+		if query == keep {
+			continue
+		}
+		if oldest == nil || cached.lastUse.Before(oldest.lastUse) {
+			oldestQuery, oldest = query, cached
+		}
+	}
+	if oldest != nil {
+		sc.discard(oldestQuery, oldest)
+	}
+}
+
+// withStmt calls fn with the cached statement of query. If fn or Prepare fails
+// with a retryable error, the statement is removed from cache and fn is retried
+// once with a freshly prepared one.
+func (sc *stmtCacher) withStmt(ctx context.Context, query string, fn func(stmt *sql.Stmt) error) error {
+	for retried := false; ; retried = true {
+		cached, err := sc.acquire(ctx, query)
+		if err == nil {
+			err = fn(cached.stmt)
+			sc.release(cached)
+		}
+		if err == nil || retried || !sc.retryable(err) {
+			return err
+		}
+		if cached != nil {
+			sc.evict(query, cached)
+		}
+	}
+}
+
+// evict discards cached statement of query unless it was replaced already
+func (sc *stmtCacher) evict(query string, cached *cachedStmt) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.cache[query] == cached {
+		sc.discard(query, cached)
+	}
+}
+
+// Stats returns current counters of the cache.
+func (sc *stmtCacher) Stats() StmtCacheStats {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	stats := sc.stats
+	stats.Size = len(sc.cache)
+	return stats
+}
+
+func (sc *stmtCacher) ExecContext(ctx context.Context, query string, args ...interface{}) (res sql.Result, err error) {
+	err = sc.withStmt(ctx, query, func(stmt *sql.Stmt) (err error) {
+		res, err = stmt.ExecContext(ctx, args...)
+		return
+	})
+	return
 }
 
 func (sc *stmtCacher) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
-	stmt, err := sc.PrepareContext(ctx, query)
-	if err != nil {
+	err = sc.withStmt(ctx, query, func(stmt *sql.Stmt) (err error) {
+		rows, err = stmt.QueryContext(ctx, args...)
 		return
-	}
-	return stmt.QueryContext(ctx, args...)
+	})
+	return
 }
 
 func (sc *stmtCacher) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
-	stmt, err := sc.PrepareContext(ctx, query)
+	var row *sql.Row
+	err := sc.withStmt(ctx, query, func(stmt *sql.Stmt) error {
+		row = stmt.QueryRowContext(ctx, args...)
+		return row.Err()
+	})
 	if err != nil {
 		return &Row{err: err}
 	}
-	return stmt.QueryRowContext(ctx, args...)
+	return row
 }
 
 func (sc *stmtCacher) Prepare(query string) (*sql.Stmt, error) {
@@ -112,3 +420,12 @@ func NewStmtCacheProxy(db *sql.DB) DBProxyBeginner {
 func (sp *stmtCacheProxy) Begin() (*sql.Tx, error) {
 	return sp.db.Begin()
 }
+
+func (sp *stmtCacheProxy) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return sp.db.BeginTx(ctx, opts)
+}
+
+// Stats returns current counters of the statement cache.
+func (sp *stmtCacheProxy) Stats() StmtCacheStats {
+	return sp.DBProxy.(StmtCacheStatsReporter).Stats()
+}
@@ -1,7 +1,13 @@
 package sqrl
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -17,3 +23,363 @@ func TestStmtCacherPrepare(t *testing.T) {
 	sc.Prepare(query)
 	assert.Equal(t, 1, db.PrepareCount, "expected 1 Prepare, got %d", db.PrepareCount)
 }
+
+func TestStmtCacherMaxAge(t *testing.T) {
+	db := &DBStub{}
+	sc := NewStmtCacher(db, WithMaxAge(time.Hour)).(*stmtCacher)
+	query := "SELECT 1"
+
+	sc.Prepare(query)
+	sc.Prepare(query)
+	assert.Equal(t, 1, db.PrepareCount)
+
+	sc.cache[query].lastUse = time.Now().Add(-2 * time.Hour)
+	sc.Prepare(query)
+	assert.Equal(t, 2, db.PrepareCount)
+
+	sc.cache[query].lastUse = time.Now().Add(-2 * time.Hour)
+	sc.closeExpired(time.Now())
+	assert.Empty(t, sc.cache)
+}
+
+func TestStmtCacherContext(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sc := NewStmtCacherContext(ctx, db).(*stmtCacher)
+	assert.Equal(t, defaultStmtMaxAge, sc.maxAge)
+
+	_, err := sc.Exec("UPDATE a SET b = 1")
+	assert.NoError(t, err)
+	_, err = sc.Exec("UPDATE a SET b = 1")
+	assert.NoError(t, err)
+
+	prepared, closed := d.counts("UPDATE a SET b = 1")
+	assert.Equal(t, 1, prepared)
+	assert.Equal(t, 0, closed)
+
+	cancel()
+	select {
+	case <-sc.done:
+	case <-time.After(time.Second):
+		t.Fatal("cleanup goroutine did not stop")
+	}
+
+	_, closed = d.counts("UPDATE a SET b = 1")
+	assert.Equal(t, 1, closed)
+	assert.Empty(t, sc.cache)
+
+	_, err = sc.Exec("UPDATE a SET b = 1")
+	assert.Equal(t, ErrStmtCacherClosed, err)
+}
+
+func TestStmtCacherContextCleanup(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sc := NewStmtCacherContext(ctx, db, WithMaxAge(10*time.Millisecond)).(*stmtCacher)
+
+	_, err := sc.Exec("DELETE FROM a")
+	assert.NoError(t, err)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, closed := d.counts("DELETE FROM a"); closed > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expired statement was not closed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestStmtCacherMaxStatements(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	sc := NewStmtCacher(db, WithMaxStatements(2)).(*stmtCacher)
+
+	_, err := sc.Exec("SELECT 1")
+	assert.NoError(t, err)
+	_, err = sc.Exec("SELECT 2")
+	assert.NoError(t, err)
+	sc.cache["SELECT 1"].lastUse = time.Now().Add(-time.Minute)
+	sc.cache["SELECT 2"].lastUse = time.Now().Add(-2 * time.Minute)
+
+	_, err = sc.Exec("SELECT 1")
+	assert.NoError(t, err)
+	_, err = sc.Exec("SELECT 3")
+	assert.NoError(t, err)
+
+	assert.Len(t, sc.cache, 2)
+	assert.Contains(t, sc.cache, "SELECT 1")
+	assert.Contains(t, sc.cache, "SELECT 3")
+
+	prepared, closed := d.counts("SELECT 1")
+	assert.Equal(t, 1, prepared)
+	assert.Equal(t, 0, closed)
+
+	prepared, closed = d.counts("SELECT 2")
+	assert.Equal(t, 1, prepared)
+	assert.Equal(t, 1, closed)
+}
+
+func TestStmtCacherMaxStatementsConcurrent(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	const maxStatements = 3
+	sc := NewStmtCacher(db, WithMaxStatements(maxStatements)).(*stmtCacher)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_, err := sc.Exec(fmt.Sprintf("SELECT %d", (g+i)%7))
+				assert.NoError(t, err)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Len(t, sc.cache, maxStatements)
+
+	live := 0
+	for i := 0; i < 7; i++ {
+		query := fmt.Sprintf("SELECT %d", i)
+		prepared, closed := d.counts(query)
+		assert.True(t, closed <= prepared, "%s closed %d times, prepared %d times", query, closed, prepared)
+		live += prepared - closed
+		if _, ok := sc.cache[query]; ok {
+			assert.Equal(t, 1, prepared-closed, "cached %s must be open", query)
+		}
+	}
+	assert.Equal(t, maxStatements, live)
+}
+
+func TestStmtCacherMaxStatementsPrepared(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	sc := NewStmtCacher(db, WithMaxStatements(1)).(*stmtCacher)
+
+	stmt, err := sc.Prepare("SELECT 1")
+	assert.NoError(t, err)
+	_, err = stmt.Exec()
+	assert.NoError(t, err)
+
+	_, err = sc.Prepare("SELECT 2")
+	assert.NoError(t, err)
+	assert.NotContains(t, sc.cache, "SELECT 1")
+
+	_, closed := d.counts("SELECT 1")
+	assert.Equal(t, 1, closed)
+	_, err = stmt.Exec()
+	assert.Error(t, err)
+
+	_, err = sc.Exec("SELECT 1")
+	assert.NoError(t, err)
+	prepared, _ := d.counts("SELECT 1")
+	assert.Equal(t, 2, prepared)
+}
+
+func TestStmtCacherMaxStatementsPreparedConcurrent(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	const maxStatements = 2
+	sc := NewStmtCacher(db, WithMaxStatements(maxStatements)).(*stmtCacher)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_, err := sc.Prepare(fmt.Sprintf("SELECT %d", g*100+i))
+				assert.NoError(t, err)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Len(t, sc.cache, maxStatements)
+
+	live := 0
+	for i := 0; i < 800; i++ {
+		prepared, closed := d.counts(fmt.Sprintf("SELECT %d", i))
+		assert.Equal(t, 1, prepared)
+		live += prepared - closed
+	}
+	assert.Equal(t, maxStatements, live)
+}
+
+func TestStmtCacherEvictInUse(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	sc := NewStmtCacher(db, WithMaxStatements(1)).(*stmtCacher)
+
+	cached, err := sc.acquire(context.Background(), "SELECT 1")
+	assert.NoError(t, err)
+	_, err = sc.Exec("SELECT 2")
+	assert.NoError(t, err)
+	assert.NotContains(t, sc.cache, "SELECT 1")
+
+	_, err = cached.stmt.Exec()
+	assert.NoError(t, err)
+	_, closed := d.counts("SELECT 1")
+	assert.Equal(t, 0, closed)
+
+	sc.release(cached)
+	_, closed = d.counts("SELECT 1")
+	assert.Equal(t, 1, closed)
+}
+
+// flakyPreparer fails the first Prepare with err
+type flakyPreparer struct {
+	*sql.DB
+	err error
+}
+
+func (p *flakyPreparer) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	if err := p.err; err != nil {
+		p.err = nil
+		return nil, err
+	}
+	return p.DB.PrepareContext(ctx, query)
+}
+
+func TestStmtCacherRetryStale(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	sc := NewStmtCacher(db).(*stmtCacher)
+	query := "SELECT * FROM a"
+
+	_, err := sc.Exec(query)
+	assert.NoError(t, err)
+
+	d.fail(query, errors.New("pq: cached plan must not change result type"))
+	_, err = sc.Exec(query)
+	assert.NoError(t, err)
+
+	prepared, closed := d.counts(query)
+	assert.Equal(t, 2, prepared)
+	assert.Equal(t, 1, closed)
+
+	d.fail(query, errors.New("Error 1615: Prepared statement needs to be re-prepared"))
+	rows, err := sc.Query(query)
+	assert.NoError(t, err)
+	rows.Close()
+
+	d.fail(query, errors.New(`pq: prepared statement "1" does not exist`))
+	err = sc.QueryRow(query).Scan()
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	prepared, closed = d.counts(query)
+	assert.Equal(t, 4, prepared)
+	assert.Equal(t, 3, closed)
+}
+
+func TestStmtCacherRetryOnce(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	query := "SELECT 1"
+	stale := errors.New("stale")
+	sc := NewStmtCacher(db, WithRetryableErrors(func(err error) bool { return err == stale }))
+
+	d.fail(query, errors.New("syntax error"))
+	_, err := sc.Exec(query)
+	assert.EqualError(t, err, "syntax error")
+	prepared, _ := d.counts(query)
+	assert.Equal(t, 1, prepared)
+
+	d.fail(query, stale)
+	_, err = sc.Exec(query)
+	assert.NoError(t, err)
+	prepared, _ = d.counts(query)
+	assert.Equal(t, 2, prepared)
+}
+
+func TestStmtCacherRetryPrepare(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	query := "SELECT 1"
+	sc := NewStmtCacher(&flakyPreparer{DB: db, err: errors.New("ERROR: cached plan must not change result type")})
+
+	_, err := sc.Exec(query)
+	assert.NoError(t, err)
+	prepared, _ := d.counts(query)
+	assert.Equal(t, 1, prepared)
+
+	sc = NewStmtCacher(&flakyPreparer{DB: db, err: errors.New("connection refused")})
+	_, err = sc.Exec(query)
+	assert.EqualError(t, err, "connection refused")
+}
+
+func TestStmtCacherStats(t *testing.T) {
+	db, _ := openFakeDB(t)
+	defer db.Close()
+
+	sc := NewStmtCacher(db, WithMaxStatements(2), WithMaxAge(time.Hour))
+	r, ok := sc.(StmtCacheStatsReporter)
+	assert.True(t, ok)
+	assert.Equal(t, StmtCacheStats{}, r.Stats())
+
+	sc.Prepare("SELECT 1")
+	sc.Prepare("SELECT 1")
+	sc.Prepare("SELECT 2")
+	assert.Equal(t, StmtCacheStats{Hits: 1, Misses: 2, Size: 2}, r.Stats())
+
+	sc.Prepare("SELECT 3")
+	assert.Equal(t, StmtCacheStats{Hits: 1, Misses: 3, Evictions: 1, Size: 2}, r.Stats())
+
+	sc.(*stmtCacher).closeExpired(time.Now().Add(2 * time.Hour))
+	assert.Equal(t, StmtCacheStats{Hits: 1, Misses: 3, Evictions: 3, Size: 0}, r.Stats())
+}
+
+func TestStmtCacherStatsConcurrent(t *testing.T) {
+	db, _ := openFakeDB(t)
+	defer db.Close()
+
+	sc := NewStmtCacher(db, WithMaxStatements(3))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				sc.Prepare(fmt.Sprintf("SELECT %d", (g+i)%5))
+				sc.(StmtCacheStatsReporter).Stats()
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	stats := sc.(StmtCacheStatsReporter).Stats()
+	assert.Equal(t, uint64(8*50), stats.Hits+stats.Misses)
+	assert.Equal(t, stats.Misses-3, stats.Evictions)
+	assert.Equal(t, 3, stats.Size)
+}
+
+func TestStmtCacheProxyStats(t *testing.T) {
+	db, _ := openFakeDB(t)
+	defer db.Close()
+
+	sp := NewStmtCacheProxy(db)
+	sp.Prepare("SELECT 1")
+	sp.Prepare("SELECT 1")
+
+	stats := sp.(StmtCacheStatsReporter).Stats()
+	assert.Equal(t, StmtCacheStats{Hits: 1, Misses: 1, Size: 1}, stats)
+}
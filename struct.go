@@ -0,0 +1,173 @@
+package sqrl
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// structField maps a column to a field of a struct
+type structField struct {
+	column string
+	index  []int
+}
+
+// structFields returns fields of struct type t in declaration order.
+//
+// Column name is taken from "db" tag and falls back to field name. Fields
+// tagged with `db:"-"` and unexported fields are skipped, fields of embedded
+// structs without "db" tag are included as if they were fields of t.
+func structFields(t reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("db"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+
+		if f.Anonymous && tag == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for _, ef := range structFields(ft) {
+					ef.index = append([]int{i}, ef.index...)
+					fields = append(fields, ef)
+				}
+				continue
+			}
+		}
+
+		if f.PkgPath != "" {
+			continue
+		}
+
+		column := tag
+		if column == "" {
+			column = f.Name
+		}
+		fields = append(fields, structField{column: column, index: []int{i}})
+	}
+	return fields
+}
+
+// structValue returns value of the field at index in v. Fields of nil embedded
+// pointers are nil.
+func structValue(v reflect.Value, index []int) interface{} {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v.Interface()
+}
+
+// structFieldAddr returns pointer to the field at index in addressable struct v,
+// allocating nil embedded pointers on the way.
+func structFieldAddr(v reflect.Value, index []int) (interface{}, error) {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return nil, fmt.Errorf("cannot allocate unexported embedded %s", v.Type())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v.Addr().Interface(), nil
+}
+
+// fieldsByColumns returns fields of struct type t mapped to columns. Columns are
+// matched with field columns exactly first and case insensitively then.
+func fieldsByColumns(t reflect.Type, columns []string) ([]structField, error) {
+	fields := structFields(t)
+	result := make([]structField, len(columns))
+	for i, column := range columns {
+		found := false
+		for _, f := range fields {
+			if f.column == column {
+				result[i], found = f, true
+				break
+			}
+		}
+		for j := 0; !found && j < len(fields); j++ {
+			if strings.EqualFold(fields[j].column, column) {
+				result[i], found = fields[j], true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("column %q is not mapped to a field of %s", column, t)
+		}
+	}
+	return result, nil
+}
+
+// indirectStruct dereferences pointers and interfaces until a struct is met.
+func indirectStruct(v reflect.Value) (reflect.Value, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v, fmt.Errorf("expected struct, got nil")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return v, fmt.Errorf("expected struct, not %s", v.Type())
+	}
+	return v, nil
+}
+
+// structsToValues converts a slice of structs to column names and rows of values.
+// All elements must have the same columns, in the same order.
+func structsToValues(rows interface{}) ([]string, [][]interface{}, error) {
+	rv := reflect.ValueOf(rows)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, nil, fmt.Errorf("expected slice of structs, not %T", rows)
+	}
+
+	var columns []string
+	values := make([][]interface{}, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		v, err := indirectStruct(rv.Index(i))
+		if err != nil {
+			return nil, nil, fmt.Errorf("element %d: %v", i, err)
+		}
+
+		fields := structFields(v.Type())
+		if i == 0 {
+			columns = make([]string, len(fields))
+			for j, f := range fields {
+				columns[j] = f.column
+			}
+		} else if !sameColumns(columns, fields) {
+			return nil, nil, fmt.Errorf("element %d: fields of %s do not match columns %v", i, v.Type(), columns)
+		}
+
+		row := make([]interface{}, len(fields))
+		for j, f := range fields {
+			row[j] = structValue(v, f.index)
+		}
+		values = append(values, row)
+	}
+	return columns, values, nil
+}
+
+func sameColumns(columns []string, fields []structField) bool {
+	if len(columns) != len(fields) {
+		return false
+	}
+	for i, f := range fields {
+		if columns[i] != f.column {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package sqrl
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type structBase struct {
+	ID int `db:"id"`
+}
+
+type structTagged struct {
+	Name string `db:"name,omitempty"`
+}
+
+type structSample struct {
+	*structBase
+	Tagged  structTagged `db:"tagged"`
+	Skipped string       `db:"-"`
+	Plain   int
+	hidden  int
+}
+
+func TestStructFields(t *testing.T) {
+	fields := structFields(reflect.TypeOf(structSample{}))
+
+	expected := []structField{
+		{column: "id", index: []int{0, 0}},
+		{column: "tagged", index: []int{1}},
+		{column: "Plain", index: []int{3}},
+	}
+	assert.Equal(t, expected, fields)
+}
+
+func TestStructsToValues(t *testing.T) {
+	rows := []structSample{
+		{structBase: &structBase{ID: 1}, Plain: 2},
+		{Plain: 3},
+	}
+
+	columns, values, err := structsToValues(rows)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "tagged", "Plain"}, columns)
+	assert.Equal(t, [][]interface{}{
+		{1, structTagged{}, 2},
+		{nil, structTagged{}, 3},
+	}, values)
+}
@@ -0,0 +1,126 @@
+package sqrl
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// TruncateBuilder builds SQL TRUNCATE statements, e.g. to clean up tables in
+// test fixtures with the same Runner as other queries.
+type TruncateBuilder struct {
+	StatementBuilderType
+
+	tables          []string
+	restartIdentity bool
+	cascade         bool
+}
+
+// NewTruncateBuilder creates new instance of TruncateBuilder
+func NewTruncateBuilder(b StatementBuilderType) *TruncateBuilder {
+	return &TruncateBuilder{StatementBuilderType: b}
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b *TruncateBuilder) RunWith(runner BaseRunner) *TruncateBuilder {
+	b.runWith = wrapRunner(runner)
+	return b
+}
+
+// WithContext sets ctx to be used by Exec, which do not take a context.
+// See SelectBuilder.WithContext.
+func (b *TruncateBuilder) WithContext(ctx context.Context) *TruncateBuilder {
+	b.ctx = ctx
+	return b
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b *TruncateBuilder) Exec() (sql.Result, error) {
+	return b.ExecContext(b.runContext())
+}
+
+// ExecContext builds and Execs the query with the Runner set by RunWith using given context.
+func (b *TruncateBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
+	if b.runWith == nil {
+		return nil, ErrRunnerNotSet
+	}
+	return ExecWithContext(ctx, b.runWith, b)
+}
+
+// Dialect sets Dialect (e.g. PostgresDialect or MySQLDialect) for the query.
+func (b *TruncateBuilder) Dialect(d Dialect) *TruncateBuilder {
+	b.dialect = d
+	b.placeholderFormat = d.PlaceholderFormat()
+	return b
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (b *TruncateBuilder) ToSql() (string, []interface{}, error) {
+	sql, args, err := b.toSqlRaw()
+	if err != nil {
+		return "", nil, err
+	}
+	return b.formatSql(sql, args)
+}
+
+// toSqlRaw builds the query leaving placeholders as is.
+func (b *TruncateBuilder) toSqlRaw() (sqlStr string, args []interface{}, err error) {
+	if len(b.tables) == 0 {
+		err = fmt.Errorf("truncate statements must specify at least one table")
+		return
+	}
+
+	switch b.dialect {
+	case SQLiteDialect:
+		err = errNotSupported("TRUNCATE", b.dialect)
+	case MySQLDialect, SQLServerDialect, OracleDialect:
+		if len(b.tables) > 1 {
+			err = errNotSupported("TRUNCATE of several tables", b.dialect)
+		} else if b.cascade && b.dialect != OracleDialect {
+			err = errNotSupported("TRUNCATE CASCADE", b.dialect)
+		} else if b.restartIdentity && b.dialect == OracleDialect {
+			err = errNotSupported("TRUNCATE RESTART IDENTITY", b.dialect)
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	sql := &bytes.Buffer{}
+	sql.WriteString("TRUNCATE TABLE ")
+	sql.WriteString(strings.Join(b.tables, ", "))
+
+	// MySQL and SQL Server always reset identity columns on TRUNCATE
+	if b.restartIdentity && b.dialect != MySQLDialect && b.dialect != SQLServerDialect {
+		sql.WriteString(" RESTART IDENTITY")
+	}
+	if b.cascade {
+		sql.WriteString(" CASCADE")
+	}
+
+	sqlStr = sql.String()
+	return
+}
+
+// Tables adds tables to be truncated.
+func (b *TruncateBuilder) Tables(tables ...string) *TruncateBuilder {
+	b.tables = append(b.tables, tables...)
+	return b
+}
+
+// RestartIdentity resets sequences owned by columns of truncated tables:
+// "TRUNCATE TABLE ... RESTART IDENTITY". MySQL and SQL Server always reset
+// them, so the option is omitted for these dialects.
+func (b *TruncateBuilder) RestartIdentity() *TruncateBuilder {
+	b.restartIdentity = true
+	return b
+}
+
+// Cascade truncates tables referencing truncated tables by foreign keys too:
+// "TRUNCATE TABLE ... CASCADE".
+func (b *TruncateBuilder) Cascade() *TruncateBuilder {
+	b.cascade = true
+	return b
+}
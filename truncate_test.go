@@ -0,0 +1,74 @@
+package sqrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateBuilderToSql(t *testing.T) {
+	sql, args, err := Truncate("a", "b").RestartIdentity().Cascade().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "TRUNCATE TABLE a, b RESTART IDENTITY CASCADE", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = Truncate("a").Tables("b").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "TRUNCATE TABLE a, b", sql)
+}
+
+func TestTruncateBuilderToSqlErr(t *testing.T) {
+	_, _, err := Truncate().ToSql()
+	assert.Error(t, err)
+}
+
+func TestTruncateBuilderDialects(t *testing.T) {
+	sql, _, err := Truncate("a", "b").RestartIdentity().Cascade().Dialect(PostgresDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "TRUNCATE TABLE a, b RESTART IDENTITY CASCADE", sql)
+
+	sql, _, err = Truncate("a").RestartIdentity().Dialect(MySQLDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "TRUNCATE TABLE a", sql)
+
+	sql, _, err = Truncate("a").RestartIdentity().Dialect(SQLServerDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "TRUNCATE TABLE a", sql)
+
+	sql, _, err = Truncate("a").Cascade().Dialect(OracleDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "TRUNCATE TABLE a CASCADE", sql)
+
+	_, _, err = Truncate("a", "b").Dialect(MySQLDialect).ToSql()
+	assert.EqualError(t, err, "TRUNCATE of several tables is not supported by MySQL dialect")
+
+	_, _, err = Truncate("a").Cascade().Dialect(SQLServerDialect).ToSql()
+	assert.EqualError(t, err, "TRUNCATE CASCADE is not supported by SQL Server dialect")
+
+	_, _, err = Truncate("a").RestartIdentity().Dialect(OracleDialect).ToSql()
+	assert.EqualError(t, err, "TRUNCATE RESTART IDENTITY is not supported by Oracle dialect")
+
+	_, _, err = Truncate("a").Dialect(SQLiteDialect).ToSql()
+	assert.EqualError(t, err, "TRUNCATE is not supported by SQLite dialect")
+}
+
+func TestTruncateBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+	b := Truncate("test").RestartIdentity().RunWith(db)
+
+	expectedSql := "TRUNCATE TABLE test RESTART IDENTITY"
+
+	b.Exec()
+	assert.Equal(t, expectedSql, db.LastExecSql)
+	assert.Empty(t, db.LastExecArgs)
+
+	db.LastExecSql = ""
+	b.ExecContext(context.TODO())
+	assert.Equal(t, expectedSql, db.LastExecSql)
+}
+
+func TestTruncateBuilderNoRunner(t *testing.T) {
+	_, err := Truncate("test").Exec()
+	assert.Equal(t, ErrRunnerNotSet, err)
+}
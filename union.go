@@ -0,0 +1,218 @@
+package sqrl
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+type unionPart struct {
+	op    string
+	query Sqlizer
+}
+
+// UnionBuilder builds SQL statements combining SELECTs with UNION, UNION ALL,
+// INTERSECT and EXCEPT.
+//
+// Each query is wrapped in parentheses: "(SELECT ...) UNION (SELECT ...)".
+// ORDER BY, LIMIT and OFFSET are applied to the whole combination.
+type UnionBuilder struct {
+	StatementBuilderType
+
+	parts    []unionPart
+	orderBys []string
+
+	limit       uint64
+	limitValid  bool
+	offset      uint64
+	offsetValid bool
+}
+
+// NewUnionBuilder creates new instance of UnionBuilder starting with given query.
+// The UnionBuilder runs with settings of b, including the context set by
+// WithContext; call WithContext of the result to replace it.
+func NewUnionBuilder(b StatementBuilderType, query Sqlizer) *UnionBuilder {
+	return &UnionBuilder{
+		StatementBuilderType: b,
+		parts:                []unionPart{{query: query}},
+	}
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Query.
+func (b *UnionBuilder) RunWith(runner BaseRunner) *UnionBuilder {
+	b.runWith = wrapRunner(runner)
+	return b
+}
+
+// WithContext sets ctx to be used by Query, QueryRow and Scan, which do not take a context.
+// See SelectBuilder.WithContext.
+func (b *UnionBuilder) WithContext(ctx context.Context) *UnionBuilder {
+	b.ctx = ctx
+	return b
+}
+
+// Query builds and Querys the query with the Runner set by RunWith.
+func (b *UnionBuilder) Query() (*sql.Rows, error) {
+	return b.QueryContext(b.runContext())
+}
+
+// QueryContext builds and Querys the query with the Runner set by RunWith in given context.
+func (b *UnionBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	if b.runWith == nil {
+		return nil, ErrRunnerNotSet
+	}
+	return QueryWithContext(ctx, b.runWith, b)
+}
+
+// QueryRow builds and QueryRows the query with the Runner set by RunWith.
+func (b *UnionBuilder) QueryRow() RowScanner {
+	return b.QueryRowContext(b.runContext())
+}
+
+// QueryRowContext builds and QueryRows the query with the Runner set by RunWith in given context.
+func (b *UnionBuilder) QueryRowContext(ctx context.Context) RowScanner {
+	if b.runWith == nil {
+		return &Row{err: ErrRunnerNotSet}
+	}
+	queryRower, ok := b.runWith.(QueryRowerContext)
+	if !ok {
+		return &Row{err: ErrRunnerNotQueryRunnerContext}
+	}
+	return QueryRowWithContext(ctx, queryRower, b)
+}
+
+// Scan is a shortcut for QueryRow().Scan.
+func (b *UnionBuilder) Scan(dest ...interface{}) error {
+	return b.QueryRow().Scan(dest...)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// query. Placeholders are numbered across all combined queries.
+func (b *UnionBuilder) PlaceholderFormat(f PlaceholderFormat) *UnionBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// Dialect sets Dialect (e.g. PostgresDialect or MySQLDialect) for the query.
+// PlaceholderFormat is set to the one used by the dialect.
+func (b *UnionBuilder) Dialect(d Dialect) *UnionBuilder {
+	b.dialect = d
+	b.placeholderFormat = d.PlaceholderFormat()
+	return b
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (b *UnionBuilder) ToSql() (string, []interface{}, error) {
+	sql, args, err := b.toSqlRaw()
+	if err != nil {
+		return "", nil, err
+	}
+	return b.formatSql(sql, args)
+}
+
+// toSqlRaw builds the query leaving placeholders as is.
+func (b *UnionBuilder) toSqlRaw() (sqlStr string, args []interface{}, err error) {
+	if len(b.parts) < 2 {
+		err = fmt.Errorf("union statements must have at least two queries")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	for i, p := range b.parts {
+		if p.query == nil {
+			err = fmt.Errorf("union statements cannot have nil queries")
+			return
+		}
+		if i > 0 {
+			sql.WriteString(" ")
+			sql.WriteString(p.op)
+			sql.WriteString(" ")
+		}
+
+		var querySql string
+		var queryArgs []interface{}
+		querySql, queryArgs, err = nestedToSql(p.query)
+		if err != nil {
+			return
+		}
+		sql.WriteString("(")
+		sql.WriteString(querySql)
+		sql.WriteString(")")
+		args = append(args, queryArgs...)
+	}
+
+	if len(b.orderBys) > 0 {
+		sql.WriteString(" ORDER BY ")
+		sql.WriteString(strings.Join(b.orderBys, ", "))
+	}
+
+	args, err = appendLimitOffset(sql, b.dialect, len(b.orderBys) > 0, b.limit, b.limitValid, b.offset, b.offsetValid, args)
+	if err != nil {
+		return
+	}
+
+	sqlStr = sql.String()
+	return
+}
+
+func (b *UnionBuilder) combine(op string, query Sqlizer) *UnionBuilder {
+	b.parts = append(b.parts, unionPart{op: op, query: query})
+	return b
+}
+
+// Union adds a query combined with UNION.
+func (b *UnionBuilder) Union(query Sqlizer) *UnionBuilder {
+	return b.combine("UNION", query)
+}
+
+// UnionAll adds a query combined with UNION ALL.
+func (b *UnionBuilder) UnionAll(query Sqlizer) *UnionBuilder {
+	return b.combine("UNION ALL", query)
+}
+
+// Intersect adds a query combined with INTERSECT.
+func (b *UnionBuilder) Intersect(query Sqlizer) *UnionBuilder {
+	return b.combine("INTERSECT", query)
+}
+
+// Except adds a query combined with EXCEPT.
+func (b *UnionBuilder) Except(query Sqlizer) *UnionBuilder {
+	return b.combine("EXCEPT", query)
+}
+
+// OrderBy adds ORDER BY expressions to the combined query.
+func (b *UnionBuilder) OrderBy(orderBys ...string) *UnionBuilder {
+	b.orderBys = append(b.orderBys, orderBys...)
+	return b
+}
+
+// Limit sets a LIMIT clause on the combined query.
+func (b *UnionBuilder) Limit(limit uint64) *UnionBuilder {
+	b.limit = limit
+	b.limitValid = true
+	return b
+}
+
+// Offset sets a OFFSET clause on the combined query.
+func (b *UnionBuilder) Offset(offset uint64) *UnionBuilder {
+	b.offset = offset
+	b.offsetValid = true
+	return b
+}
+
+// RemoveLimit removes LIMIT clause from the combined query.
+func (b *UnionBuilder) RemoveLimit() *UnionBuilder {
+	b.limit = 0
+	b.limitValid = false
+	return b
+}
+
+// RemoveOffset removes OFFSET clause from the combined query.
+func (b *UnionBuilder) RemoveOffset() *UnionBuilder {
+	b.offset = 0
+	b.offsetValid = false
+	return b
+}
@@ -0,0 +1,133 @@
+package sqrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnionBuilderToSql(t *testing.T) {
+	sql, args, err := Select("id").From("a").Where("x = ?", 1).
+		Union(Select("id").From("b").Where("y = ?", 2)).
+		UnionAll(Select("id").From("c").Where("z = ?", 3)).
+		Intersect(Select("id").From("d")).
+		Except(Select("id").From("e").Where("w = ?", 4)).
+		OrderBy("id DESC").
+		Limit(10).
+		Offset(20).
+		ToSql()
+
+	assert.NoError(t, err)
+
+	expectedSql := "(SELECT id FROM a WHERE x = ?) UNION (SELECT id FROM b WHERE y = ?) " +
+		"UNION ALL (SELECT id FROM c WHERE z = ?) INTERSECT (SELECT id FROM d) " +
+		"EXCEPT (SELECT id FROM e WHERE w = ?) ORDER BY id DESC LIMIT ? OFFSET ?"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 4, uint64(10), uint64(20)}, args)
+}
+
+func TestUnionBuilderPlaceholders(t *testing.T) {
+	sql, args, err := Select("id").From("a").Where("x = ?", 1).PlaceholderFormat(Dollar).
+		Union(Select("id").From("b").Where("y = ? AND z = ?", 2, 3).PlaceholderFormat(Dollar)).
+		Union(Select("id").From("c").Where("w = ?", 4)).
+		ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "(SELECT id FROM a WHERE x = $1) UNION (SELECT id FROM b WHERE y = $2 AND z = $3) "+
+		"UNION (SELECT id FROM c WHERE w = $4)", sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 4}, args)
+
+	sql, _, err = Select("id").From("a").
+		Union(Select("id").From("b").Where("y = ?", 2)).
+		Dialect(SQLServerDialect).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(SELECT id FROM a) UNION (SELECT id FROM b WHERE y = @p1)", sql)
+}
+
+func TestUnionBuilderAsSubquery(t *testing.T) {
+	u := Select("user_id").From("admins").Union(Select("user_id").From("owners").Where("org = ?", 7))
+
+	sql, args, err := Select("*").From("users").Where(Eq{"id": u}).PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id IN ((SELECT user_id FROM admins) UNION (SELECT user_id FROM owners WHERE org = $1))", sql)
+	assert.Equal(t, []interface{}{7}, args)
+}
+
+func TestUnionBuilderToSqlErr(t *testing.T) {
+	_, _, err := NewUnionBuilder(StatementBuilder, Select("id")).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select("id").From("a").Union(Select()).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select("id").From("a").Union(nil).ToSql()
+	assert.Error(t, err)
+}
+
+func TestUnionBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+	b := Select("a").Union(Select("b")).RunWith(db)
+
+	expectedSql := "(SELECT a) UNION (SELECT b)"
+
+	b.Query()
+	assert.Equal(t, expectedSql, db.LastQuerySql)
+
+	b.QueryRow()
+	assert.Equal(t, expectedSql, db.LastQueryRowSql)
+
+	b.QueryContext(context.TODO())
+	assert.Equal(t, expectedSql, db.LastQuerySql)
+
+	b.QueryRowContext(context.TODO())
+	assert.Equal(t, expectedSql, db.LastQueryRowSql)
+
+	err := b.Scan()
+	assert.NoError(t, err)
+}
+
+func TestUnionBuilderInheritsContext(t *testing.T) {
+	db := &DBStub{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "first")
+	other := context.WithValue(context.Background(), ctxKey{}, "other")
+
+	b := Select("a").WithContext(ctx).Union(Select("b").WithContext(other)).RunWith(db)
+	b.Query()
+	assert.Equal(t, ctx, db.LastCtx)
+
+	b.WithContext(other).Query()
+	assert.Equal(t, other, db.LastCtx)
+
+	Select("a").WithContext(ctx).Explain().RunWith(db).Query()
+	assert.Equal(t, ctx, db.LastCtx)
+}
+
+func TestUnionBuilderNoRunner(t *testing.T) {
+	b := Select("a").Union(Select("b"))
+
+	_, err := b.Query()
+	assert.Equal(t, ErrRunnerNotSet, err)
+
+	err = b.Scan()
+	assert.Equal(t, ErrRunnerNotSet, err)
+}
+
+func TestUnionBuilderLimitDialect(t *testing.T) {
+	sql, args, err := Select("id").From("a").Union(Select("id").From("b")).
+		OrderBy("id").Limit(5).Dialect(SQLServerDialect).ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "(SELECT id FROM a) UNION (SELECT id FROM b) ORDER BY id OFFSET @p1 ROWS FETCH NEXT @p2 ROWS ONLY", sql)
+	assert.Equal(t, []interface{}{uint64(0), uint64(5)}, args)
+}
+
+func TestUnionBuilderRemoveLimitOffset(t *testing.T) {
+	sql, args, err := Select("id").From("a").Union(Select("id").From("b")).
+		Limit(5).Offset(10).RemoveLimit().RemoveOffset().ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "(SELECT id FROM a) UNION (SELECT id FROM b)", sql)
+	assert.Empty(t, args)
+}
@@ -5,8 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"sort"
-	"strconv"
+	"io"
 	"strings"
 )
 
@@ -15,6 +14,31 @@ type setClause struct {
 	value  interface{}
 }
 
+func appendSetClauses(clauses []setClause, w io.Writer, d Dialect, args []interface{}) ([]interface{}, error) {
+	setSqls := make([]string, len(clauses))
+	for i, setClause := range clauses {
+		valSql, valArgs, err := valueToSql(setClause.value, d)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, valArgs...)
+		setSqls[i] = fmt.Sprintf("%s = %s", setClause.column, valSql)
+	}
+	_, err := io.WriteString(w, strings.Join(setSqls, ", "))
+	return args, err
+}
+
+// sortedSetClauses converts a map of column names and values to set clauses
+// ordered by column name.
+func sortedSetClauses(clauses map[string]interface{}) []setClause {
+	keys := sortedKeys(clauses)
+	result := make([]setClause, len(keys))
+	for i, key := range keys {
+		result[i] = setClause{column: key, value: clauses[key]}
+	}
+	return result
+}
+
 // Builder
 
 // UpdateBuilder builds SQL UPDATE statements.
@@ -22,6 +46,7 @@ type UpdateBuilder struct {
 	StatementBuilderType
 
 	returning
+	with
 
 	prefixes   exprs
 	table      string
@@ -49,9 +74,16 @@ func (b *UpdateBuilder) RunWith(runner BaseRunner) *UpdateBuilder {
 	return b
 }
 
+// WithContext sets ctx to be used by Exec, Query, QueryRow and Scan, which do not take a context.
+// See SelectBuilder.WithContext.
+func (b *UpdateBuilder) WithContext(ctx context.Context) *UpdateBuilder {
+	b.ctx = ctx
+	return b
+}
+
 // Exec builds and Execs the query with the Runner set by RunWith.
 func (b *UpdateBuilder) Exec() (sql.Result, error) {
-	return b.ExecContext(context.Background())
+	return b.ExecContext(b.runContext())
 }
 
 // ExecContext builds and Execs the query with the Runner set by RunWith using given context.
@@ -64,7 +96,7 @@ func (b *UpdateBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
 
 // Query builds and Querys the query with the Runner set by RunWith.
 func (b *UpdateBuilder) Query() (*sql.Rows, error) {
-	return b.QueryContext(context.Background())
+	return b.QueryContext(b.runContext())
 }
 
 // QueryContext builds and runs the query using given context and Query command.
@@ -77,7 +109,7 @@ func (b *UpdateBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
 
 // QueryRow builds and QueryRows the query with the Runner set by RunWith.
 func (b *UpdateBuilder) QueryRow() RowScanner {
-	return b.QueryRowContext(context.Background())
+	return b.QueryRowContext(b.runContext())
 }
 
 // QueryRowContext builds and runs the query using given context.
@@ -104,8 +136,30 @@ func (b *UpdateBuilder) PlaceholderFormat(f PlaceholderFormat) *UpdateBuilder {
 	return b
 }
 
+// Dialect sets Dialect (e.g. PostgresDialect or MySQLDialect) for the query.
+// PlaceholderFormat is set to the one used by the dialect.
+func (b *UpdateBuilder) Dialect(d Dialect) *UpdateBuilder {
+	b.dialect = d
+	b.placeholderFormat = d.PlaceholderFormat()
+	return b
+}
+
 // ToSql builds the query into a SQL string and bound args.
-func (b *UpdateBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+func (b *UpdateBuilder) ToSql() (string, []interface{}, error) {
+	sql, args, err := b.toSqlRaw()
+	if err != nil {
+		return "", nil, err
+	}
+	return b.formatSql(sql, args)
+}
+
+// MustSql builds the query like ToSql and panics on error, see MustSql.
+func (b *UpdateBuilder) MustSql() (string, []interface{}) {
+	return MustSql(b)
+}
+
+// toSqlRaw builds the query leaving placeholders as is.
+func (b *UpdateBuilder) toSqlRaw() (sqlStr string, args []interface{}, err error) {
 	if len(b.table) == 0 {
 		err = fmt.Errorf("update statements must specify a table")
 		return
@@ -118,36 +172,32 @@ func (b *UpdateBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 	sql := &bytes.Buffer{}
 
 	if len(b.prefixes) > 0 {
-		args, _ = b.prefixes.AppendToSql(sql, " ", args)
+		args, err = b.prefixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
+	if len(b.ctes) > 0 {
+		args, err = b.with.AppendToSql(sql, args)
+		if err != nil {
+			return
+		}
+	}
+
 	sql.WriteString("UPDATE ")
 	sql.WriteString(b.table)
 
 	sql.WriteString(" SET ")
-	setSqls := make([]string, len(b.setClauses))
-	for i, setClause := range b.setClauses {
-		var valSql string
-		switch typedVal := setClause.value.(type) {
-		case Sqlizer:
-			var valArgs []interface{}
-			valSql, valArgs, err = typedVal.ToSql()
-			if err != nil {
-				return
-			}
-			args = append(args, valArgs...)
-		default:
-			valSql = "?"
-			args = append(args, typedVal)
-		}
-		setSqls[i] = fmt.Sprintf("%s = %s", setClause.column, valSql)
+	args, err = appendSetClauses(b.setClauses, sql, b.exprDialect(), args)
+	if err != nil {
+		return
 	}
-	sql.WriteString(strings.Join(setSqls, ", "))
 
 	if len(b.fromParts) > 0 {
 		sql.WriteString(" FROM ")
-		args, err = appendToSql(b.fromParts, sql, ", ", args)
+		args, err = appendToSql(b.fromParts, sql, ", ", b.exprDialect(), args)
 		if err != nil {
 			return
 		}
@@ -155,7 +205,7 @@ func (b *UpdateBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.whereParts) > 0 {
 		sql.WriteString(" WHERE ")
-		args, err = appendToSql(b.whereParts, sql, " AND ", args)
+		args, err = appendToSql(b.whereParts, sql, " AND ", b.exprDialect(), args)
 		if err != nil {
 			return
 		}
@@ -166,19 +216,16 @@ func (b *UpdateBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 		sql.WriteString(strings.Join(b.orderBys, ", "))
 	}
 
-	// TODO: limit == 0 and offswt == 0 are valid. Need to go dbr way and implement offsetValid and limitValid
-	if b.limitValid {
-		sql.WriteString(" LIMIT ")
-		sql.WriteString(strconv.FormatUint(b.limit, 10))
+	if err = checkDmlLimitOffset("UPDATE", b.dialect, b.limitValid, b.offsetValid); err != nil {
+		return
 	}
-
-	if b.offsetValid {
-		sql.WriteString(" OFFSET ")
-		sql.WriteString(strconv.FormatUint(b.offset, 10))
+	args, err = appendLimitOffset(sql, b.dialect, len(b.orderBys) > 0, b.limit, b.limitValid, b.offset, b.offsetValid, args)
+	if err != nil {
+		return
 	}
 
 	if len(b.returning) > 0 {
-		args, err = b.returning.AppendToSql(sql, args)
+		args, err = b.returning.AppendToSql(sql, b.dialect, args)
 		if err != nil {
 			return
 		}
@@ -186,16 +233,36 @@ func (b *UpdateBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = b.suffixes.AppendToSql(sql, " ", args)
+		args, err = b.suffixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 	}
 
-	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	sqlStr = sql.String()
 	return
 }
 
 // SQL methods
 
-// Prefix adds an expression to the beginning of the query
+// With adds a common table expression to WITH clause of the query:
+// "WITH name AS (query) UPDATE ...".
+//
+// The name is written verbatim, so column list could be given as well, e.g. "t(a, b)".
+func (b *UpdateBuilder) With(name string, query Sqlizer) *UpdateBuilder {
+	b.with.With(name, query)
+	return b
+}
+
+// WithRecursive adds a common table expression to WITH clause of the query,
+// making the clause "WITH RECURSIVE".
+func (b *UpdateBuilder) WithRecursive(name string, query Sqlizer) *UpdateBuilder {
+	b.with.WithRecursive(name, query)
+	return b
+}
+
+// Prefix adds an expression to the beginning of the query.
+// Sqlizer args are embedded in place of their placeholders.
 func (b *UpdateBuilder) Prefix(sql string, args ...interface{}) *UpdateBuilder {
 	b.prefixes = append(b.prefixes, Expr(sql, args...))
 	return b
@@ -215,17 +282,7 @@ func (b *UpdateBuilder) Set(column string, value interface{}) *UpdateBuilder {
 
 // SetMap is a convenience method which calls .Set for each key/value pair in clauses.
 func (b *UpdateBuilder) SetMap(clauses map[string]interface{}) *UpdateBuilder {
-	keys := make([]string, len(clauses))
-	i := 0
-	for key := range clauses {
-		keys[i] = key
-		i++
-	}
-	sort.Strings(keys)
-	for _, key := range keys {
-		val, _ := clauses[key]
-		b = b.Set(key, val)
-	}
+	b.setClauses = append(b.setClauses, sortedSetClauses(clauses)...)
 	return b
 }
 
@@ -237,6 +294,14 @@ func (b *UpdateBuilder) Where(pred interface{}, args ...interface{}) *UpdateBuil
 	return b
 }
 
+// WhereIf adds WHERE expressions like Where does, but only if cond is true.
+func (b *UpdateBuilder) WhereIf(cond bool, pred interface{}, args ...interface{}) *UpdateBuilder {
+	if cond {
+		b.Where(pred, args...)
+	}
+	return b
+}
+
 // From adds tables to FROM clause of the query.
 //
 // UPDATE ... FROM is an PostgreSQL specific extension
@@ -258,12 +323,71 @@ func (b *UpdateBuilder) FromSelect(from *SelectBuilder, alias string) *UpdateBui
 	return b
 }
 
+// FromValues adds a VALUES list to FROM clause of the query, useful to update
+// many rows with different values at once:
+//     UPDATE t SET name = v.name FROM (VALUES (?, ?), (?, ?)) AS v(id, name) WHERE t.id = v.id
+//
+// Column names are set with ValuesBuilder.Columns.
+func (b *UpdateBuilder) FromValues(values *ValuesBuilder, alias string) *UpdateBuilder {
+	b.fromParts = append(b.fromParts, fromValuesPart{values: values, alias: alias})
+	return b
+}
+
 // OrderBy adds ORDER BY expressions to the query.
 func (b *UpdateBuilder) OrderBy(orderBys ...string) *UpdateBuilder {
 	b.orderBys = append(b.orderBys, orderBys...)
 	return b
 }
 
+// OrderByIf adds ORDER BY expressions like OrderBy does, but only if cond is true.
+func (b *UpdateBuilder) OrderByIf(cond bool, orderBys ...string) *UpdateBuilder {
+	if cond {
+		b.OrderBy(orderBys...)
+	}
+	return b
+}
+
+// Clone returns a copy of the builder, so parts could be added to or removed
+// from the copy without changing the original query, see SelectBuilder.Clone.
+func (b *UpdateBuilder) Clone() *UpdateBuilder {
+	c := *b
+	c.returning = returning(cloneSqlizers(b.returning))
+	c.with = b.with.clone()
+	c.prefixes = append(exprs(nil), b.prefixes...)
+	c.fromParts = cloneSqlizers(b.fromParts)
+	c.setClauses = append([]setClause(nil), b.setClauses...)
+	c.whereParts = cloneSqlizers(b.whereParts)
+	c.orderBys = cloneStrings(b.orderBys)
+	c.suffixes = append(exprs(nil), b.suffixes...)
+	return &c
+}
+
+// RemoveSet removes SET clauses of column from the query.
+// Like the other methods, it changes the builder, use Clone to keep the
+// original query.
+func (b *UpdateBuilder) RemoveSet(column string) *UpdateBuilder {
+	clauses := b.setClauses[:0:0]
+	for _, c := range b.setClauses {
+		if c.column != column {
+			clauses = append(clauses, c)
+		}
+	}
+	b.setClauses = clauses
+	return b
+}
+
+// RemoveWhere removes all WHERE expressions from the query.
+func (b *UpdateBuilder) RemoveWhere() *UpdateBuilder {
+	b.whereParts = nil
+	return b
+}
+
+// RemoveOrderBys removes ORDER BY clause from the query.
+func (b *UpdateBuilder) RemoveOrderBys() *UpdateBuilder {
+	b.orderBys = nil
+	return b
+}
+
 // Limit sets a LIMIT clause on the query.
 func (b *UpdateBuilder) Limit(limit uint64) *UpdateBuilder {
 	b.limit = limit
@@ -294,7 +418,8 @@ func (b *UpdateBuilder) ReturningSelect(from *SelectBuilder, alias string) *Upda
 	return b
 }
 
-// Suffix adds an expression to the end of the query
+// Suffix adds an expression to the end of the query.
+// Sqlizer args are embedded in place of their placeholders.
 func (b *UpdateBuilder) Suffix(sql string, args ...interface{}) *UpdateBuilder {
 	b.suffixes = append(b.suffixes, Expr(sql, args...))
 	return b
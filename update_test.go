@@ -25,11 +25,11 @@ func TestUpdateBuilderToSql(t *testing.T) {
 	expectedSql :=
 		"WITH prefix AS ? " +
 			"UPDATE a SET b = ? + 1, c = ? WHERE d = ? " +
-			"ORDER BY e LIMIT 4 OFFSET 5 " +
+			"ORDER BY e LIMIT ? OFFSET ? " +
 			"RETURNING ?"
 	assert.Equal(t, expectedSql, sql)
 
-	expectedArgs := []interface{}{0, 1, 2, 3, 6}
+	expectedArgs := []interface{}{0, 1, 2, 3, uint64(4), uint64(5), 6}
 	assert.Equal(t, expectedArgs, args)
 }
 
@@ -77,10 +77,10 @@ func TestUpdateBuilderZeroOffsetLimit(t *testing.T) {
 	sql, args, err := qb.ToSql()
 	assert.NoError(t, err)
 
-	expectedSql := "UPDATE a SET b = ? LIMIT 0 OFFSET 0"
+	expectedSql := "UPDATE a SET b = ? LIMIT ? OFFSET ?"
 	assert.Equal(t, expectedSql, sql)
 
-	expectedArgs := []interface{}{true}
+	expectedArgs := []interface{}{true, uint64(0), uint64(0)}
 	assert.Equal(t, expectedArgs, args)
 }
 
@@ -148,3 +148,179 @@ func TestUpdateBuilderNoRunner(t *testing.T) {
 	err = b.Scan()
 	assert.Equal(t, ErrRunnerNotSet, err)
 }
+
+func TestUpdateBuilderReturningPlaceholders(t *testing.T) {
+	b := Update("a").
+		Set("foo", 1).
+		Where("id = ?", 42).
+		Returning("id", "foo").
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE a SET foo = $1 WHERE id = $2 RETURNING id, foo", sql)
+	assert.Equal(t, []interface{}{1, 42}, args)
+}
+
+func TestUpdateBuilderSetSubquery(t *testing.T) {
+	b := Update("orders").
+		Set("status", "paid").
+		Set("counter", Expr("counter + ?", 1)).
+		Set("total", Select("SUM(price)").From("items").Where("items.order_id = orders.id AND qty > ?", 0)).
+		SetMap(map[string]interface{}{
+			"updated_by": Select("id").From("users").Where(Eq{"name": "admin"}),
+			"note":       "recalculated",
+		}).
+		Where("id = ?", 42).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "UPDATE orders SET status = $1, counter = counter + $2, " +
+		"total = (SELECT SUM(price) FROM items WHERE items.order_id = orders.id AND qty > $3), " +
+		"note = $4, updated_by = (SELECT id FROM users WHERE name = $5) " +
+		"WHERE id = $6"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{"paid", 1, 0, "recalculated", "admin", 42}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestUpdateBuilderSetSubqueryErr(t *testing.T) {
+	_, _, err := Update("a").Set("b", Select()).ToSql()
+	assert.Error(t, err)
+}
+
+func TestUpdateBuilderFromArgsOrder(t *testing.T) {
+	b := Update("accounts").
+		Set("balance", Expr("balance + t.amount * ?", 2)).
+		Set("updated", true).
+		From("owners o").
+		FromSelect(Select("account_id", "SUM(amount) AS amount").From("txns").Where("day = ?", "mon").GroupBy("account_id"), "t").
+		Where("accounts.id = t.account_id AND accounts.owner_id = o.id").
+		Where(Eq{"o.active": true}).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "UPDATE accounts SET balance = balance + t.amount * $1, updated = $2 " +
+		"FROM owners o, (SELECT account_id, SUM(amount) AS amount FROM txns WHERE day = $3 GROUP BY account_id) AS t " +
+		"WHERE accounts.id = t.account_id AND accounts.owner_id = o.id AND o.active = $4"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{2, true, "mon", true}, args)
+}
+
+func TestUpdateBuilderExprArgsOrder(t *testing.T) {
+	values := Values(Expr("GREATEST(?, ?)", 3, 4), 5).Columns("id", "score")
+	sql, args, err := Update("users").
+		Set("score", Expr("LEAST(?, ?)", 1, 2)).
+		FromSelect(Select("*").FromValues(values, "v"), "n").
+		Where("users.id = n.id AND n.score BETWEEN ?", Expr("? AND ?", 6, 7)).
+		PlaceholderFormat(Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	expectedSql := "UPDATE users SET score = LEAST($1, $2) " +
+		"FROM (SELECT * FROM (VALUES (GREATEST($3, $4),$5)) AS v(id, score)) AS n " +
+		"WHERE users.id = n.id AND n.score BETWEEN $6 AND $7"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 4, 5, 6, 7}, args)
+}
+
+func TestUpdateBuilderPrefixSqlizer(t *testing.T) {
+	sql, args, err := Update("a").
+		Prefix("?;", Expr("SET LOCAL lock_timeout = ?", "1s")).
+		Set("b", 1).
+		Suffix("RETURNING ?", Expr("b + ?", 2)).
+		ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SET LOCAL lock_timeout = ?; UPDATE a SET b = ? RETURNING b + ?", sql)
+	assert.Equal(t, []interface{}{"1s", 1, 2}, args)
+}
+
+func TestUpdateBuilderIf(t *testing.T) {
+	b := Update("users").Set("active", false).Where("id = ?", 1)
+	before := *b
+
+	b.WhereIf(false, "name = ?", "moe").OrderByIf(false, "name")
+	assert.Equal(t, before, *b)
+
+	sql, args, err := b.WhereIf(true, "name = ?", "moe").OrderByIf(true, "name").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET active = ? WHERE id = ? AND name = ? ORDER BY name", sql)
+	assert.Equal(t, []interface{}{false, 1, "moe"}, args)
+}
+
+func TestUpdateBuilderSetMapDeterministic(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		sql, args, err := Update("t").
+			SetMap(map[string]interface{}{"c": 3, "a": 1, "d": Expr("d + ?", 4), "b": 2}).
+			Where(Eq{"y": 6, "x": 5}).
+			ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, "UPDATE t SET a = ?, b = ?, c = ?, d = d + ? WHERE x = ? AND y = ?", sql)
+		assert.Equal(t, []interface{}{1, 2, 3, 4, 5, 6}, args)
+	}
+}
+
+func TestUpdateBuilderFromValues(t *testing.T) {
+	values := Values(1, "a").Values(2, "b").Columns("id", "name")
+	sql, args, err := Update("t").
+		Set("name", Expr("v.name")).
+		Set("updated_by", "admin").
+		FromValues(values, "v").
+		Where("t.id = v.id AND t.locked = ?", false).
+		Dialect(PostgresDialect).
+		ToSql()
+
+	assert.NoError(t, err)
+	expectedSql := "UPDATE t SET name = v.name, updated_by = $1 " +
+		"FROM (VALUES ($2,$3),($4,$5)) AS v(id, name) " +
+		"WHERE t.id = v.id AND t.locked = $6"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"admin", 1, "a", 2, "b", false}, args)
+}
+
+func TestUpdateBuilderRemoveParts(t *testing.T) {
+	b := Update("t").Set("a", 1).Set("b", 2).Set("a", 3).Set("c", 4).
+		Where("x = ?", 5).OrderBy("y").Limit(6)
+
+	sql, args, err := b.Clone().RemoveSet("b").RemoveWhere().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ?, a = ?, c = ? ORDER BY y LIMIT ?", sql)
+	assert.Equal(t, []interface{}{1, 3, 4, uint64(6)}, args)
+
+	sql, args, err = b.RemoveSet("a").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET b = ?, c = ? WHERE x = ? ORDER BY y LIMIT ?", sql)
+	assert.Equal(t, []interface{}{2, 4, 5, uint64(6)}, args)
+
+	sql, args, err = b.RemoveSet("d").RemoveWhere().RemoveOrderBys().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET b = ?, c = ? LIMIT ?", sql)
+	assert.Equal(t, []interface{}{2, 4, uint64(6)}, args)
+
+	_, _, err = b.RemoveSet("b").RemoveSet("c").ToSql()
+	assert.Error(t, err)
+}
+
+func TestUpdateBuilderLimitOffsetDialects(t *testing.T) {
+	b := Update("t").Set("a", 1).OrderBy("id").Limit(10)
+
+	_, _, err := b.Dialect(SQLServerDialect).ToSql()
+	assert.EqualError(t, err, "LIMIT and OFFSET of UPDATE is not supported by SQL Server dialect")
+
+	_, _, err = b.Dialect(OracleDialect).ToSql()
+	assert.EqualError(t, err, "LIMIT and OFFSET of UPDATE is not supported by Oracle dialect")
+
+	sql, _, err := Update("t").Set("a", 1).OrderBy("id").Dialect(SQLServerDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = @p1 ORDER BY id", sql)
+
+	sql, _, err = Update("t").Set("a", 1).Limit(10).Dialect(MySQLDialect).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ? LIMIT ?", sql)
+}
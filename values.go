@@ -0,0 +1,141 @@
+package sqrl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValuesBuilder builds SQL VALUES lists, which could be used as tables in
+// FROM clause of a query, see SelectBuilder.FromValues.
+type ValuesBuilder struct {
+	StatementBuilderType
+
+	columns  []string
+	values   [][]interface{}
+	rowFuncs []func() ([]interface{}, bool)
+}
+
+// NewValuesBuilder creates new instance of ValuesBuilder
+func NewValuesBuilder(b StatementBuilderType) *ValuesBuilder {
+	return &ValuesBuilder{StatementBuilderType: b}
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// query.
+func (b *ValuesBuilder) PlaceholderFormat(f PlaceholderFormat) *ValuesBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// Dialect sets Dialect (e.g. PostgresDialect or MySQLDialect) for the query.
+// PlaceholderFormat is set to the one used by the dialect.
+func (b *ValuesBuilder) Dialect(d Dialect) *ValuesBuilder {
+	b.dialect = d
+	b.placeholderFormat = d.PlaceholderFormat()
+	return b
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (b *ValuesBuilder) ToSql() (string, []interface{}, error) {
+	sql, args, err := b.toSqlRaw()
+	if err != nil {
+		return "", nil, err
+	}
+	return b.formatSql(sql, args)
+}
+
+// MustSql builds the query like ToSql and panics on error, see MustSql.
+func (b *ValuesBuilder) MustSql() (string, []interface{}) {
+	return MustSql(b)
+}
+
+// toSqlRaw builds the query leaving placeholders as is.
+func (b *ValuesBuilder) toSqlRaw() (sqlStr string, args []interface{}, err error) {
+	if len(b.values) == 0 {
+		err = errors.New("values statements must have at least one row")
+		return
+	}
+	width := len(b.values[0])
+	if width == 0 {
+		err = errors.New("values statements cannot have empty rows")
+		return
+	}
+	for r, row := range b.values {
+		if len(row) != width {
+			err = fmt.Errorf("row %d has %d values, expected %d", r, len(row), width)
+			return
+		}
+	}
+	if len(b.columns) > 0 && len(b.columns) != width {
+		err = fmt.Errorf("values statements have %d columns, but rows have %d values", len(b.columns), width)
+		return
+	}
+
+	sql := &bytes.Buffer{}
+	sql.WriteString("VALUES ")
+
+	valuesStrings := make([]string, len(b.values))
+	for r, row := range b.values {
+		var rowArgs []interface{}
+		valuesStrings[r], rowArgs, err = valuesRowToSql(row, b.exprDialect())
+		if err != nil {
+			return
+		}
+		args = append(args, rowArgs...)
+	}
+	sql.WriteString(strings.Join(valuesStrings, ","))
+
+	sqlStr = sql.String()
+	return
+}
+
+// Columns sets names of the columns exposed by VALUES table used in FROM clause:
+// "(VALUES ...) AS t(columns)".
+func (b *ValuesBuilder) Columns(columns ...string) *ValuesBuilder {
+	b.columns = columns
+	return b
+}
+
+// Values adds a single row's values to the query.
+func (b *ValuesBuilder) Values(values ...interface{}) *ValuesBuilder {
+	b.values = append(b.values, values)
+	return b
+}
+
+// AddFunc adds a generator of rows for ExecStream, see InsertBuilder.AddFunc.
+func (b *ValuesBuilder) AddFunc(next func() ([]interface{}, bool)) *ValuesBuilder {
+	b.rowFuncs = append(b.rowFuncs, next)
+	return b
+}
+
+// ExecStream inserts the rows into table into, in batches of up to batchSize
+// rows. Columns set by Columns are the columns of the INSERT statement.
+// See InsertBuilder.ExecStream.
+func (b *ValuesBuilder) ExecStream(ctx context.Context, runner ExecerContext, into string, batchSize int) (int64, error) {
+	insert := NewInsertBuilder(b.StatementBuilderType).Into(into).Columns(b.columns...)
+	insert.values = b.values
+	insert.rowFuncs = b.rowFuncs
+	return insert.ExecStream(ctx, runner, batchSize)
+}
+
+// fromValuesPart is a VALUES list in FROM clause: "(VALUES ...) AS alias(columns)"
+type fromValuesPart struct {
+	values *ValuesBuilder
+	alias  string
+}
+
+func (p fromValuesPart) ToSql() (sql string, args []interface{}, err error) {
+	sql, args, err = nestedToSql(p.values)
+	if err != nil {
+		return
+	}
+
+	sql = fmt.Sprintf("(%s) AS %s", sql, p.alias)
+	if len(p.values.columns) > 0 {
+		sql = fmt.Sprintf("%s(%s)", sql, strings.Join(p.values.columns, ", "))
+	}
+	return
+}
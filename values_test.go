@@ -0,0 +1,81 @@
+package sqrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValuesBuilderToSql(t *testing.T) {
+	b := Values(1, "a").
+		Values(2, Expr("UPPER(?)", "b")).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "VALUES ($1,$2),($3,UPPER($4))", sql)
+	assert.Equal(t, []interface{}{1, "a", 2, "b"}, args)
+}
+
+func TestValuesBuilderToSqlErr(t *testing.T) {
+	_, _, err := Values().ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Values(1, "a").Columns("id").ToSql()
+	assert.Error(t, err)
+}
+
+func TestValuesBuilderRowWidths(t *testing.T) {
+	_, _, err := Values(1, 2).Values(3).ToSql()
+	assert.Error(t, err)
+	assert.Equal(t, "row 1 has 1 values, expected 2", err.Error())
+
+	_, _, err = Values(1).Values(2, 3).ToSql()
+	assert.Error(t, err)
+	assert.Equal(t, "row 1 has 2 values, expected 1", err.Error())
+
+	_, _, err = Values().Values().ToSql()
+	assert.Error(t, err)
+	assert.Equal(t, "values statements cannot have empty rows", err.Error())
+
+	_, _, err = Select("*").FromValues(Values(1, 2).Values(3, 4).Values(5), "t").ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderFromValues(t *testing.T) {
+	sql, args, err := Select("*").
+		FromValues(Values(1, "a").Values(2, "b").Columns("id", "name"), "t").
+		Where("t.id > ?", 0).
+		PlaceholderFormat(Dollar).
+		ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM (VALUES ($1,$2),($3,$4)) AS t(id, name) WHERE t.id > $5", sql)
+	assert.Equal(t, []interface{}{1, "a", 2, "b", 0}, args)
+
+	sql, _, err = Select("*").FromValues(Values(1), "t").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM (VALUES (?)) AS t", sql)
+
+	_, _, err = Select("*").FromValues(Values(1, "a").Columns("id", "name", "extra"), "t").ToSql()
+	assert.Error(t, err)
+}
+
+func TestValuesBuilderExecStream(t *testing.T) {
+	i := 0
+	gen := func() ([]interface{}, bool) {
+		i++
+		return []interface{}{i, "gen"}, i <= 3
+	}
+
+	db := &rowsExecer{}
+	n, err := Values(0, "first").Columns("id", "name").AddFunc(gen).PlaceholderFormat(Dollar).
+		ExecStream(context.Background(), db, "users", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), n)
+	assert.Equal(t, []string{
+		"INSERT INTO users (id,name) VALUES ($1,$2),($3,$4)",
+		"INSERT INTO users (id,name) VALUES ($1,$2),($3,$4)",
+	}, db.queries)
+}
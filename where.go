@@ -9,16 +9,19 @@ func newWherePart(pred interface{}, args ...interface{}) Sqlizer {
 }
 
 func (p wherePart) ToSql() (sql string, args []interface{}, err error) {
+	return p.toSqlDialect(NoDialect)
+}
+
+func (p wherePart) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
 	switch pred := p.pred.(type) {
 	case nil:
 		// no-op
 	case Sqlizer:
-		return pred.ToSql()
+		return dialectToSql(pred, d)
 	case map[string]interface{}:
-		return Eq(pred).ToSql()
+		return Eq(pred).toSqlDialect(d)
 	case string:
-		sql = pred
-		args = p.args
+		return Expr(pred, p.args...).toSqlDialect(d)
 	default:
 		err = fmt.Errorf("expected string-keyed map or string, not %T", pred)
 	}
@@ -15,14 +15,44 @@ func TestWherePartsAppendToSql(t *testing.T) {
 		newWherePart(Eq{"y": 2}),
 	}
 	sql := &bytes.Buffer{}
-	args, _ := appendToSql(parts, sql, " AND ", []interface{}{})
+	args, _ := appendToSql(parts, sql, " AND ", NoDialect, []interface{}{})
 	assert.Equal(t, "x = ? AND y = ?", sql.String())
 	assert.Equal(t, []interface{}{1, 2}, args)
 }
 
+func TestWherePartsAppendToSqlEmptyFirst(t *testing.T) {
+	parts := []Sqlizer{
+		newWherePart(nil),
+		newWherePart(""),
+		newWherePart("x = ?", 1),
+		newWherePart(nil),
+		newWherePart(Eq{"y": 2}),
+	}
+	sql := &bytes.Buffer{}
+	args, _ := appendToSql(parts, sql, " AND ", NoDialect, []interface{}{})
+	assert.Equal(t, "x = ? AND y = ?", sql.String())
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestWherePartsOrder(t *testing.T) {
+	sql, args, err := Select("*").From("t").
+		Where(Eq{"b": 2, "a": 1}).
+		Where(Expr("c = ? OR d IN (?)", 3, Select("d").From("u").Where("e = ?", 4))).
+		Where(nil).
+		Where(Gt{"f": 5}).
+		Where("g = ? AND h = ?", 6, 7).
+		ToSql()
+
+	assert.NoError(t, err)
+	expectedSql := "SELECT * FROM t WHERE a = ? AND b = ? AND c = ? OR d IN (SELECT d FROM u WHERE e = ?) " +
+		"AND f > ? AND g = ? AND h = ?"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 4, 5, 6, 7}, args)
+}
+
 func TestWherePartsAppendToSqlErr(t *testing.T) {
 	parts := []Sqlizer{newWherePart(1)}
-	_, err := appendToSql(parts, &bytes.Buffer{}, "", []interface{}{})
+	_, err := appendToSql(parts, &bytes.Buffer{}, "", NoDialect, []interface{}{})
 	assert.Error(t, err)
 }
 
@@ -54,3 +84,10 @@ func TestWherePartMap(t *testing.T) {
 	test(m)
 	test(Eq(m))
 }
+
+func TestWherePartStringSqlizerArgs(t *testing.T) {
+	sql, args, err := newWherePart("x = ? AND y > ?", Expr("COALESCE(?, ?)", 1, 2), 3).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "x = COALESCE(?, ?) AND y > ?", sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+}
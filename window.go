@@ -0,0 +1,86 @@
+package sqrl
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// windowSpec is a window definition, the part of "OVER (...)" within parentheses
+type windowSpec []Sqlizer
+
+func (ws windowSpec) ToSql() (string, []interface{}, error) {
+	sql := &bytes.Buffer{}
+	args, err := appendToSql(ws, sql, " ", NoDialect, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql.String(), args, nil
+}
+
+// PartitionBy builds "PARTITION BY ..." part of a window definition.
+//
+// See Over.
+func PartitionBy(columns ...string) Sqlizer {
+	return Expr("PARTITION BY " + strings.Join(columns, ", "))
+}
+
+// OrderBy builds "ORDER BY ..." part of a window definition.
+//
+// See Over.
+func OrderBy(orderBys ...string) Sqlizer {
+	return Expr("ORDER BY " + strings.Join(orderBys, ", "))
+}
+
+type overExpr struct {
+	fn     Sqlizer
+	window string
+	spec   windowSpec
+}
+
+// Over builds window function call "fn OVER (parts...)".
+// Parts are joined with spaces, any Sqlizer can be used for a frame clause.
+// Ex:
+//
+//	.Column(Alias(Over(Expr("row_number()"), PartitionBy("dept"), OrderBy("salary DESC")), "rn"))
+//	.Column(Over("sum(amount)", OrderBy("day"), Expr("ROWS BETWEEN ? PRECEDING AND CURRENT ROW", 6)))
+func Over(fn interface{}, parts ...Sqlizer) Sqlizer {
+	return overExpr{fn: newPart(fn), spec: parts}
+}
+
+// OverWindow builds window function call "fn OVER name" which refers to
+// a window defined with SelectBuilder.Window.
+func OverWindow(fn interface{}, name string) Sqlizer {
+	return overExpr{fn: newPart(fn), window: name}
+}
+
+func (e overExpr) ToSql() (string, []interface{}, error) {
+	fnSql, args, err := e.fn.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(e.window) > 0 {
+		return fmt.Sprintf("%s OVER %s", fnSql, e.window), args, nil
+	}
+
+	specSql, specArgs, err := e.spec.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s OVER (%s)", fnSql, specSql), append(args, specArgs...), nil
+}
+
+// namedWindow is a part of WINDOW clause: "name AS (spec)"
+type namedWindow struct {
+	name string
+	spec windowSpec
+}
+
+func (w namedWindow) ToSql() (sql string, args []interface{}, err error) {
+	sql, args, err = w.spec.ToSql()
+	if err == nil {
+		sql = fmt.Sprintf("%s AS (%s)", w.name, sql)
+	}
+	return
+}
@@ -0,0 +1,69 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOver(t *testing.T) {
+	sql, args, err := Over(Expr("row_number()"), PartitionBy("dept"), OrderBy("salary DESC")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "row_number() OVER (PARTITION BY dept ORDER BY salary DESC)", sql)
+	assert.Empty(t, args)
+
+	sql, args, err = Over("sum(amount)").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "sum(amount) OVER ()", sql)
+	assert.Empty(t, args)
+}
+
+func TestOverArgs(t *testing.T) {
+	b := Select("id").
+		Column(Alias(Over(Expr("coalesce(sum(amount), ?)", 0),
+			PartitionBy("account_id"),
+			OrderBy("day"),
+			Expr("ROWS BETWEEN ? PRECEDING AND CURRENT ROW", 6)), "total")).
+		From("payments").
+		Where("day > ?", "2018-01-01").
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT id, (coalesce(sum(amount), $1) OVER " +
+		"(PARTITION BY account_id ORDER BY day ROWS BETWEEN $2 PRECEDING AND CURRENT ROW)) AS total " +
+		"FROM payments WHERE day > $3"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{0, 6, "2018-01-01"}, args)
+}
+
+func TestSelectBuilderWindow(t *testing.T) {
+	b := Select("name").
+		Column(OverWindow("rank()", "w")).
+		Column(OverWindow(Expr("lag(salary, ?)", 1), "w")).
+		From("employees").
+		GroupBy("dept", "name", "salary").
+		Having("count(*) > ?", 2).
+		Window("w", PartitionBy("dept"), OrderBy("salary DESC")).
+		Window("w2", Expr("w"), Expr("ROWS ? PRECEDING", 3)).
+		OrderBy("name")
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT name, rank() OVER w, lag(salary, ?) OVER w FROM employees " +
+		"GROUP BY dept, name, salary HAVING count(*) > ? " +
+		"WINDOW w AS (PARTITION BY dept ORDER BY salary DESC), w2 AS (w ROWS ? PRECEDING) " +
+		"ORDER BY name"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+}
+
+func TestOverErr(t *testing.T) {
+	_, _, err := Over(1).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Over("rank()", Select()).ToSql()
+	assert.Error(t, err)
+}
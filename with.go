@@ -0,0 +1,53 @@
+package sqrl
+
+import (
+	"fmt"
+	"io"
+)
+
+// cte is a common table expression: "name AS (query)"
+type cte struct {
+	name  string
+	query Sqlizer
+}
+
+func (c cte) ToSql() (sql string, args []interface{}, err error) {
+	sql, args, err = nestedToSql(c.query)
+	if err == nil {
+		sql = fmt.Sprintf("%s AS (%s)", c.name, sql)
+	}
+	return
+}
+
+type with struct {
+	recursive bool
+	ctes      []Sqlizer
+}
+
+// clone returns a copy of w not sharing its CTEs slice.
+func (w with) clone() with {
+	w.ctes = cloneSqlizers(w.ctes)
+	return w
+}
+
+func (w *with) With(name string, query Sqlizer) {
+	w.ctes = append(w.ctes, cte{name: name, query: query})
+}
+
+func (w *with) WithRecursive(name string, query Sqlizer) {
+	w.recursive = true
+	w.With(name, query)
+}
+
+func (w *with) AppendToSql(wr io.Writer, args []interface{}) ([]interface{}, error) {
+	io.WriteString(wr, "WITH ")
+	if w.recursive {
+		io.WriteString(wr, "RECURSIVE ")
+	}
+	args, err := appendToSql(w.ctes, wr, ", ", NoDialect, args)
+	if err != nil {
+		return nil, err
+	}
+	io.WriteString(wr, " ")
+	return args, nil
+}
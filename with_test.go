@@ -0,0 +1,64 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectBuilderWith(t *testing.T) {
+	b := Select("a.x", "b.y").
+		With("a", Select("x").From("t1").Where(Eq{"x": 1})).
+		With("b(y)", Select("y").From("t2").Where("y > ?", 2)).
+		From("a").
+		Join("b ON a.x = b.y").
+		Where("a.x < ?", 3).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "WITH a AS (SELECT x FROM t1 WHERE x = $1), " +
+		"b(y) AS (SELECT y FROM t2 WHERE y > $2) " +
+		"SELECT a.x, b.y FROM a JOIN b ON a.x = b.y WHERE a.x < $3"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+}
+
+func TestSelectBuilderWithRecursive(t *testing.T) {
+	tree := Expr("SELECT id FROM nodes WHERE id = ? UNION ALL SELECT n.id FROM nodes n JOIN tree ON n.parent_id = tree.id", 1)
+	b := Select("id").WithRecursive("tree", tree).From("tree")
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "WITH RECURSIVE tree AS (SELECT id FROM nodes WHERE id = ? " +
+		"UNION ALL SELECT n.id FROM nodes n JOIN tree ON n.parent_id = tree.id) " +
+		"SELECT id FROM tree"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestWithErr(t *testing.T) {
+	_, _, err := Select("x").With("a", Select()).From("a").ToSql()
+	assert.Error(t, err)
+}
+
+func TestWriteBuildersWith(t *testing.T) {
+	ids := Select("id").From("old").Where("age > ?", 1)
+
+	sql, args, err := Insert("a").With("ids", ids).Columns("id").Select(Select("id").From("ids")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH ids AS (SELECT id FROM old WHERE age > ?) INSERT INTO a (id) SELECT id FROM ids", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args, err = Update("a").With("ids", ids).Set("foo", 2).Where("id IN (SELECT id FROM ids)").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH ids AS (SELECT id FROM old WHERE age > ?) UPDATE a SET foo = ? WHERE id IN (SELECT id FROM ids)", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, args, err = Delete("a").With("ids", ids).Where("id IN (SELECT id FROM ids)").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH ids AS (SELECT id FROM old WHERE age > ?) DELETE FROM a WHERE id IN (SELECT id FROM ids)", sql)
+	assert.Equal(t, []interface{}{1}, args)
+}